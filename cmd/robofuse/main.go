@@ -1,15 +1,26 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/robofuse/robofuse/internal/config"
 	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/internal/qbittorrent"
+	exportqbittorrent "github.com/robofuse/robofuse/pkg/export/qbittorrent"
+	"github.com/robofuse/robofuse/pkg/metrics"
+	"github.com/robofuse/robofuse/pkg/mount"
+	"github.com/robofuse/robofuse/pkg/progress"
+	"github.com/robofuse/robofuse/pkg/retry"
+	"github.com/robofuse/robofuse/pkg/rpc"
 	"github.com/robofuse/robofuse/pkg/sync"
+	"github.com/robofuse/robofuse/pkg/web"
 )
 
 const version = "1.0"
@@ -17,15 +28,19 @@ const version = "1.0"
 func main() {
 	// Define flags
 	var (
-		configPath string
-		logLevel   string
-		showHelp   bool
-		showVer    bool
+		configPath  string
+		logLevel    string
+		webAddr     string
+		replaceFlag string
+		showHelp    bool
+		showVer     bool
 	)
 
 	flag.StringVar(&configPath, "config", "", "Path to config file")
 	flag.StringVar(&configPath, "c", "", "Path to config file (shorthand)")
 	flag.StringVar(&logLevel, "log-level", "", "Log level (debug, info, warn, error)")
+	flag.StringVar(&webAddr, "web", "", "Address for the web dashboard (overrides config, watch mode only)")
+	flag.StringVar(&replaceFlag, "replace", "", "export-qbittorrent: rewrite save paths, \"from,to\"")
 	flag.BoolVar(&showHelp, "help", false, "Show help")
 	flag.BoolVar(&showHelp, "h", false, "Show help (shorthand)")
 	flag.BoolVar(&showVer, "version", false, "Show version")
@@ -59,6 +74,9 @@ func main() {
 		logger.SetLogLevel(cfg.LogLevel)
 	}
 	logger.SetLogPath(cfg.CacheDir)
+	if webAddr != "" {
+		cfg.WebAddr = webAddr
+	}
 	config.SetInstance(cfg)
 
 	log := logger.Default()
@@ -66,18 +84,49 @@ func main() {
 	// Print banner
 	printBanner()
 
+	// rootCtx is cancelled on SIGINT/SIGTERM so a long-running "watch" can
+	// drain its current cycle and persist state instead of dying mid-write.
+	rootCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	switch command {
 	case "run":
 		log.Info().Msg("Starting single sync run...")
-		runSync(cfg, false)
+		runSync(rootCtx, cfg, false)
 
 	case "watch":
 		log.Info().Msgf("Starting watch mode (interval: %ds)...", cfg.WatchModeInterval)
-		runWatch(cfg)
+		runWatch(rootCtx, cfg)
 
 	case "dry-run", "dryrun":
 		log.Info().Msg("Starting dry run (no changes will be made)...")
-		runSync(cfg, true)
+		runSync(rootCtx, cfg, true)
+
+	case "status":
+		runStatus(cfg)
+
+	case "trigger":
+		runTrigger(cfg, flag.Arg(1) == "--dry-run")
+
+	case "refresh-expiring":
+		runRefreshExpiring(cfg)
+
+	case "retry":
+		runRetry(cfg, flag.Arg(1))
+
+	case "mount":
+		if flag.Arg(1) == "" {
+			fmt.Fprintln(os.Stderr, "Usage: robofuse mount <path>")
+			os.Exit(1)
+		}
+		runMount(rootCtx, cfg, flag.Arg(1))
+
+	case "export-qbittorrent":
+		if flag.Arg(1) == "" {
+			fmt.Fprintln(os.Stderr, "Usage: robofuse export-qbittorrent <BT_backup dir> [--replace from,to]")
+			os.Exit(1)
+		}
+		runExportQBittorrent(cfg, flag.Arg(1), replaceFlag)
 
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
@@ -105,13 +154,25 @@ func printUsage() {
 Usage: robofuse [options] <command>
 
 Commands:
-  run       Run sync once and exit
-  watch     Run sync continuously in watch mode
-  dry-run   Show what would happen without making changes
+  run               Run sync once and exit
+  watch             Run sync continuously in watch mode
+  dry-run           Show what would happen without making changes
+  status              Show live status from a running "watch" instance (requires rpc_socket_path)
+  trigger             Trigger an immediate sync cycle on a running "watch" instance
+  trigger --dry-run   Trigger an immediate dry-run cycle on a running "watch" instance
+  refresh-expiring    Trigger an immediate expiring-link refresh on a running "watch" instance
+  retry list          List the pending retry queue of a running "watch" instance
+  retry flush         Force every queued retry to be attempted on the next cycle
+  retry dead          List the dead-letter queue (retries that exceeded max_retry_attempts)
+  retry clear-dead    Clear the dead-letter queue
+  mount <path>         Mount the tracked library as a read-only FUSE filesystem at path
+  export-qbittorrent <dir>  Export tracked torrents as a qBittorrent BT_backup directory
 
 Options:
   -c, --config <path>    Path to config file
   --log-level <level>    Log level (debug, info, warn, error)
+  --web <addr>           Address for the web dashboard (watch mode only)
+  --replace <from,to>    export-qbittorrent: rewrite save paths
   -v, --version          Show version
   -h, --help             Show this help
 
@@ -119,14 +180,21 @@ Examples:
   robofuse run
   robofuse --config /path/to/config.json watch
   robofuse dry-run
+  robofuse status
+  robofuse retry list
 `, version)
 }
 
-func runSync(cfg *config.Config, dryRun bool) {
+func runSync(ctx context.Context, cfg *config.Config, dryRun bool) {
 	log := logger.Default()
-	
-	service := sync.New(cfg)
-	result, err := service.Run(dryRun)
+
+	service, err := sync.New(cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to initialize sync service")
+		os.Exit(1)
+	}
+
+	result, err := service.Run(ctx, dryRun)
 	if err != nil {
 		log.Error().Err(err).Msg("Sync failed")
 		os.Exit(1)
@@ -135,19 +203,301 @@ func runSync(cfg *config.Config, dryRun bool) {
 	printSummary(result, dryRun)
 }
 
-func runWatch(cfg *config.Config) {
+func runWatch(ctx context.Context, cfg *config.Config) {
 	log := logger.Default()
-	
-	service := sync.New(cfg)
-	if err := service.Watch(); err != nil {
+
+	service, err := sync.New(cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to initialize sync service")
+		os.Exit(1)
+	}
+
+	config.OnChange(func(old, new *config.Config) {
+		if new.LogLevel != old.LogLevel {
+			logger.SetLogLevel(new.LogLevel)
+		}
+	})
+	if cfg.ResolvedPath != "" {
+		stopCh := make(chan struct{})
+		go func() {
+			if err := config.Watch(cfg.ResolvedPath, stopCh); err != nil {
+				log.Warn().Err(err).Msg("Config watcher stopped")
+			}
+		}()
+	}
+
+	if cfg.RPCSocketPath != "" {
+		server := rpc.NewServer(cfg.RPCSocketPath, service)
+		go func() {
+			if err := server.ListenAndServe(ctx); err != nil {
+				log.Warn().Err(err).Msg("RPC server stopped")
+			}
+		}()
+	}
+
+	if cfg.MetricsListenAddr != "" {
+		server := metrics.NewServer(cfg.MetricsListenAddr, cfg.AdminToken, service.Metrics(), service)
+		go func() {
+			if err := server.ListenAndServe(ctx); err != nil {
+				log.Warn().Err(err).Msg("Metrics server stopped")
+			}
+		}()
+	}
+
+	if cfg.QBittorrentListenAddr != "" {
+		server := qbittorrent.NewServer(cfg.QBittorrentListenAddr, service.RealDebridClient(), service.QBittorrentStore())
+		go func() {
+			if err := server.ListenAndServe(ctx); err != nil {
+				log.Warn().Err(err).Msg("qBittorrent shim stopped")
+			}
+		}()
+	}
+
+	if cfg.WebAddr != "" {
+		server := web.NewServer(cfg.WebAddr, cfg.AdminToken, service)
+		go func() {
+			if err := server.ListenAndServe(ctx); err != nil {
+				log.Warn().Err(err).Msg("Web dashboard stopped")
+			}
+		}()
+	}
+
+	if err := service.Watch(ctx); err != nil {
 		log.Error().Err(err).Msg("Watch mode failed")
 		os.Exit(1)
 	}
+
+	log.Info().Msg("Shutdown complete")
+}
+
+// runMount serves the tracked library as a read-only FUSE filesystem at
+// mountpoint until interrupted, resolving each file's link on demand
+// instead of relying on pre-written STRM output.
+func runMount(ctx context.Context, cfg *config.Config, mountpoint string) {
+	log := logger.Default()
+
+	service, err := sync.New(cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to initialize sync service")
+		os.Exit(1)
+	}
+
+	mountSvc := mount.New(service.Tracking(), service)
+	if err := mountSvc.Mount(ctx, mountpoint); err != nil {
+		log.Error().Err(err).Msg("Mount failed")
+		os.Exit(1)
+	}
+
+	log.Info().Msg("Unmounted")
+}
+
+// runExportQBittorrent writes a .torrent/.fastresume pair per tracked
+// torrent into destDir so a qBittorrent instance can adopt robofuse's
+// library without re-downloading. replaceArg is "from,to" or empty.
+func runExportQBittorrent(cfg *config.Config, destDir, replaceArg string) {
+	log := logger.Default()
+
+	var replaceFrom, replaceTo string
+	if replaceArg != "" {
+		parts := strings.SplitN(replaceArg, ",", 2)
+		if len(parts) != 2 {
+			fmt.Fprintln(os.Stderr, "--replace expects \"from,to\"")
+			os.Exit(1)
+		}
+		replaceFrom, replaceTo = parts[0], parts[1]
+	}
+
+	service, err := sync.New(cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to initialize sync service")
+		os.Exit(1)
+	}
+
+	exporter := exportqbittorrent.New(cfg, service.ProviderByName, service.Tracking())
+	result, err := exporter.Export(exportqbittorrent.Options{
+		DestDir:     destDir,
+		ReplaceFrom: replaceFrom,
+		ReplaceTo:   replaceTo,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Export failed")
+		os.Exit(1)
+	}
+
+	fmt.Printf("Exported %d torrent(s), %d skipped.\n", result.Exported, result.Skipped)
+	for _, e := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+}
+
+// runStatus connects to a running "watch" instance's RPC socket, prints its
+// current status, then live-prints progress events as an aligned table
+// until the stream ends (e.g. Ctrl+C).
+func runStatus(cfg *config.Config) {
+	if cfg.RPCSocketPath == "" {
+		fmt.Fprintln(os.Stderr, "rpc_socket_path is not configured")
+		os.Exit(1)
+	}
+
+	var st sync.Status
+	if err := rpc.Call(cfg.RPCSocketPath, "GetStatus", nil, &st); err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching status: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%-14s %v\n", "running:", st.Running)
+	fmt.Printf("%-14s %v\n", "paused:", st.Paused)
+	fmt.Printf("%-14s %d\n", "retry queue:", st.RetryQueued)
+	fmt.Printf("%-14s %d\n", "expiring:", st.ExpiringLinks)
+	fmt.Printf("%-14s %d\n", "org pending:", st.OrganizerPending)
+	if !st.LastRunAt.IsZero() {
+		fmt.Printf("%-14s %s\n", "last run:", st.LastRunAt.Format("2006-01-02 15:04:05"))
+	}
+	if st.LastError != "" {
+		fmt.Printf("%-14s %s\n", "last error:", st.LastError)
+	}
+	if st.LastSummary != "" {
+		fmt.Printf("%-14s %s\n", "summary:", st.LastSummary)
+	}
+
+	fmt.Println()
+	fmt.Println("Watching progress (Ctrl+C to exit)...")
+	if err := rpc.StreamProgress(cfg.RPCSocketPath, func(event progress.Event) {
+		fmt.Printf("\r%-12s [%4d/%-4d] %-6.1fs eta  %-40s", event.Phase, event.Completed, event.Total, event.ETASeconds, event.Item)
+	}); err != nil {
+		fmt.Println()
+		fmt.Fprintf(os.Stderr, "Progress stream ended: %v\n", err)
+	}
+}
+
+// runTrigger dials a running "watch" instance's RPC socket and triggers an
+// immediate sync cycle, without waiting for it to finish.
+func runTrigger(cfg *config.Config, dryRun bool) {
+	if cfg.RPCSocketPath == "" {
+		fmt.Fprintln(os.Stderr, "rpc_socket_path is not configured")
+		os.Exit(1)
+	}
+
+	params := struct {
+		DryRun bool `json:"dry_run"`
+	}{DryRun: dryRun}
+	if err := rpc.Call(cfg.RPCSocketPath, "TriggerSync", params, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error triggering sync: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Sync cycle triggered.")
+}
+
+// runRefreshExpiring dials a running "watch" instance's RPC socket and
+// triggers an immediate expiring-link refresh, without waiting for it to
+// finish.
+func runRefreshExpiring(cfg *config.Config) {
+	if cfg.RPCSocketPath == "" {
+		fmt.Fprintln(os.Stderr, "rpc_socket_path is not configured")
+		os.Exit(1)
+	}
+
+	if err := rpc.Call(cfg.RPCSocketPath, "RefreshExpiring", nil, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error triggering expiring-link refresh: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Expiring-link refresh triggered.")
+}
+
+// runRetry dials a running "watch" instance's RPC socket and serves the
+// "retry list", "retry flush", "retry dead", and "retry clear-dead"
+// subcommands.
+func runRetry(cfg *config.Config, subcommand string) {
+	if cfg.RPCSocketPath == "" {
+		fmt.Fprintln(os.Stderr, "rpc_socket_path is not configured")
+		os.Exit(1)
+	}
+
+	switch strings.ToLower(subcommand) {
+	case "list", "":
+		var items []*retry.RetryItem
+		if err := rpc.Call(cfg.RPCSocketPath, "ListRetryQueue", nil, &items); err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching retry queue: %v\n", err)
+			os.Exit(1)
+		}
+		printRetryTable(items)
+
+	case "flush":
+		var result struct {
+			Count int `json:"count"`
+		}
+		if err := rpc.Call(cfg.RPCSocketPath, "FlushRetryQueue", nil, &result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error flushing retry queue: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Flushed %d item(s); they'll be retried on the next cycle.\n", result.Count)
+
+	case "dead":
+		var items []*retry.DeadLetterItem
+		if err := rpc.Call(cfg.RPCSocketPath, "ListDeadLetterQueue", nil, &items); err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching dead-letter queue: %v\n", err)
+			os.Exit(1)
+		}
+		printDeadLetterTable(items)
+
+	case "clear-dead":
+		if err := rpc.Call(cfg.RPCSocketPath, "ClearDeadLetterQueue", nil, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error clearing dead-letter queue: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Dead-letter queue cleared.")
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown retry subcommand: %s\n", subcommand)
+		os.Exit(1)
+	}
+}
+
+func printRetryTable(items []*retry.RetryItem) {
+	if len(items) == 0 {
+		fmt.Println("Retry queue is empty.")
+		return
+	}
+
+	fmt.Printf("%-40s %-8s %-10s %-20s %s\n", "FILENAME", "RETRIES", "ERROR", "NEXT ATTEMPT", "LAST ERROR")
+	for _, item := range items {
+		fmt.Printf("%-40s %-8d %-10s %-20s %s\n",
+			truncate(item.Filename, 40),
+			item.RetryCount,
+			item.ErrorType,
+			item.NextAttempt.Format("2006-01-02 15:04:05"),
+			item.LastError,
+		)
+	}
+}
+
+func printDeadLetterTable(items []*retry.DeadLetterItem) {
+	if len(items) == 0 {
+		fmt.Println("Dead-letter queue is empty.")
+		return
+	}
+
+	fmt.Printf("%-40s %-8s %-20s %s\n", "FILENAME", "RETRIES", "FAILED AT", "REASON")
+	for _, item := range items {
+		fmt.Printf("%-40s %-8d %-20s %s\n",
+			truncate(item.Filename, 40),
+			item.RetryCount,
+			item.FailedAt.Format("2006-01-02 15:04:05"),
+			item.Reason,
+		)
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
 }
 
 func printSummary(result *sync.RunResult, dryRun bool) {
 	log := logger.Default()
-	
+
 	mode := "Sync"
 	if dryRun {
 		mode = "Dry Run"
@@ -173,7 +523,10 @@ func printSummary(result *sync.RunResult, dryRun bool) {
 	log.Info().Msgf("    Updated:         %d", result.STRMUpdated)
 	log.Info().Msgf("    Deleted:         %d", result.STRMDeleted)
 	log.Info().Msgf("    Skipped:         %d", result.STRMSkipped)
-	
+	if result.STRMDiskSkipped > 0 {
+		log.Info().Msgf("    Disk-skipped:    %d", result.STRMDiskSkipped)
+	}
+
 	if result.OrgProcessed > 0 {
 		log.Info().Msg("")
 		log.Info().Msg("  Organized:")
@@ -185,6 +538,6 @@ func printSummary(result *sync.RunResult, dryRun bool) {
 			log.Info().Msgf("    Errors:          %d", result.OrgErrors)
 		}
 	}
-	
+
 	log.Info().Msg("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 }
@@ -0,0 +1,66 @@
+// Package alldebrid implements debrid.Provider against the AllDebrid v4
+// HTTP API (https://docs.alldebrid.com).
+package alldebrid
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/internal/request"
+	"github.com/rs/zerolog"
+)
+
+const defaultHost = "https://api.alldebrid.com/v4"
+
+func init() {
+	debrid.Register("alldebrid", func(apiKey string) debrid.Provider {
+		return New(apiKey)
+	})
+}
+
+// Client is the AllDebrid API client.
+type Client struct {
+	host   string
+	apiKey string
+	client *request.Client
+	logger zerolog.Logger
+}
+
+// New creates a new AllDebrid client authenticated with apiKey.
+func New(apiKey string) *Client {
+	return &Client{
+		host:   defaultHost,
+		apiKey: apiKey,
+		client: request.New(
+			request.WithMaxRetries(5),
+			request.WithRetryableStatus(429, 502, 503),
+		),
+		logger: logger.New("alldebrid"),
+	}
+}
+
+// Name identifies this provider as "alldebrid".
+func (c *Client) Name() string {
+	return "alldebrid"
+}
+
+// apiResponse is the envelope every AllDebrid v4 endpoint responds with.
+type apiResponse struct {
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Error  *apiError       `json:"error,omitempty"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) asError() error {
+	if e == nil {
+		return nil
+	}
+	return fmt.Errorf("alldebrid: %s: %s", e.Code, e.Message)
+}
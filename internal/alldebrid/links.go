@@ -0,0 +1,38 @@
+package alldebrid
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+)
+
+// UnrestrictLink implements debrid.Provider.
+func (c *Client) UnrestrictLink(link string) (*debrid.Download, error) {
+	var data struct {
+		Link     string `json:"link"`
+		Filename string `json:"filename"`
+		Filesize int64  `json:"filesize"`
+		Host     string `json:"host"`
+		Streams  []any  `json:"streams,omitempty"`
+	}
+	if err := c.call("/link/unlock", url.Values{"link": {link}}, &data); err != nil {
+		return nil, fmt.Errorf("unlocking link: %w", err)
+	}
+
+	streamable := 0
+	if len(data.Streams) > 0 {
+		streamable = 1
+	}
+
+	return &debrid.Download{
+		Filename:   data.Filename,
+		Filesize:   data.Filesize,
+		Link:       link,
+		Host:       data.Host,
+		Download:   data.Link,
+		Streamable: streamable,
+		Generated:  time.Now(),
+	}, nil
+}
@@ -0,0 +1,215 @@
+package alldebrid
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/pkg/torrentfile"
+)
+
+// magnetStatus mirrors the shape of one entry in GET /magnet/status's
+// "magnets" array.
+type magnetStatus struct {
+	ID       int          `json:"id"`
+	Filename string       `json:"filename"`
+	Hash     string       `json:"hash"`
+	Size     int64        `json:"size"`
+	Status   string       `json:"status"`
+	StatusID int          `json:"statusCode"`
+	Links    []magnetLink `json:"links"`
+}
+
+type magnetLink struct {
+	Link     string `json:"link"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+}
+
+// toTorrent converts a magnetStatus into a provider-neutral Torrent. Ready
+// corresponds to AllDebrid statusCode 4 ("Ready").
+func (m magnetStatus) toTorrent() *debrid.Torrent {
+	status := "downloading"
+	switch {
+	case m.StatusID == 4:
+		status = "downloaded"
+	case m.StatusID >= 5:
+		status = "dead"
+	}
+
+	links := make([]string, len(m.Links))
+	files := make([]debrid.File, len(m.Links))
+	for i, l := range m.Links {
+		links[i] = l.Link
+		files[i] = debrid.File{ID: i, Path: l.Filename, Bytes: l.Size, Selected: 1}
+	}
+
+	return &debrid.Torrent{
+		ID:       strconv.Itoa(m.ID),
+		Filename: m.Filename,
+		Hash:     m.Hash,
+		Bytes:    m.Size,
+		Status:   status,
+		Progress: 100,
+		Links:    links,
+		Files:    files,
+	}
+}
+
+// call performs a GET against path with the given query values plus the
+// shared apikey/agent params, and unmarshals the "data" envelope into out.
+func (c *Client) call(path string, q url.Values, out any) error {
+	if q == nil {
+		q = url.Values{}
+	}
+	q.Set("apikey", c.apiKey)
+	q.Set("agent", "robofuse")
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?%s", c.host, path, q.Encode()), nil)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.client.MakeRequest(req)
+	if err != nil {
+		return err
+	}
+
+	var resp apiResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	if resp.Status != "success" {
+		return resp.Error.asError()
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, out)
+}
+
+// AddMagnet uploads a magnet (by infohash) and returns its AllDebrid ID.
+func (c *Client) AddMagnet(hash string) (string, error) {
+	magnet := fmt.Sprintf("magnet:?xt=urn:btih:%s", hash)
+
+	var data struct {
+		Magnets []struct {
+			ID    int       `json:"id"`
+			Ready bool      `json:"ready"`
+			Error *apiError `json:"error,omitempty"`
+		} `json:"magnets"`
+	}
+	q := url.Values{"magnets[]": {magnet}}
+	if err := c.call("/magnet/upload", q, &data); err != nil {
+		return "", fmt.Errorf("uploading magnet: %w", err)
+	}
+	if len(data.Magnets) == 0 {
+		return "", fmt.Errorf("alldebrid: upload returned no magnets")
+	}
+	if err := data.Magnets[0].Error.asError(); err != nil {
+		return "", err
+	}
+
+	c.logger.Info().Int("id", data.Magnets[0].ID).Msg("Added magnet")
+	return strconv.Itoa(data.Magnets[0].ID), nil
+}
+
+// GetTorrents implements debrid.Provider.
+func (c *Client) GetTorrents() ([]*debrid.Torrent, []*debrid.Torrent, error) {
+	var data struct {
+		Magnets []magnetStatus `json:"magnets"`
+	}
+	if err := c.call("/magnet/status", nil, &data); err != nil {
+		return nil, nil, fmt.Errorf("listing magnets: %w", err)
+	}
+
+	var downloaded, dead []*debrid.Torrent
+	for _, m := range data.Magnets {
+		t := m.toTorrent()
+		switch t.Status {
+		case "downloaded":
+			downloaded = append(downloaded, t)
+		case "dead":
+			dead = append(dead, t)
+		}
+	}
+	return downloaded, dead, nil
+}
+
+// GetTorrentInfo implements debrid.Provider.
+func (c *Client) GetTorrentInfo(torrentID string) (*debrid.Torrent, error) {
+	var data struct {
+		Magnets magnetStatus `json:"magnets"`
+	}
+	if err := c.call("/magnet/status", url.Values{"id": {torrentID}}, &data); err != nil {
+		return nil, fmt.Errorf("fetching magnet status: %w", err)
+	}
+	return data.Magnets.toTorrent(), nil
+}
+
+// SelectVideoFiles is a no-op for AllDebrid: it has no file-selection step,
+// every file in a magnet is already linkable once ready. It returns the
+// count of links available so callers can log it the same way as other
+// providers.
+func (c *Client) SelectVideoFiles(torrentID string) (int, error) {
+	info, err := c.GetTorrentInfo(torrentID)
+	if err != nil {
+		return 0, err
+	}
+	return len(info.Files), nil
+}
+
+// DeleteTorrent implements debrid.Provider.
+func (c *Client) DeleteTorrent(torrentID string) error {
+	if err := c.call("/magnet/delete", url.Values{"id": {torrentID}}, nil); err != nil {
+		return fmt.Errorf("deleting magnet: %w", err)
+	}
+	c.logger.Info().Str("id", torrentID).Msg("Deleted torrent")
+	return nil
+}
+
+// GetDownloads implements debrid.Provider. AllDebrid has no persistent
+// unrestricted-link cache, so every link is simply unrestricted fresh on
+// each sync.
+func (c *Client) GetDownloads() ([]*debrid.Download, error) {
+	return nil, nil
+}
+
+// AddTorrentFile implements debrid.Provider. AllDebrid has no native
+// byte-upload endpoint, so this derives the infohash from data and falls
+// back to AddMagnet.
+func (c *Client) AddTorrentFile(data []byte) (string, error) {
+	hash, _, _, err := torrentfile.ParseTorrentBytes(data)
+	if err != nil {
+		return "", fmt.Errorf("parsing torrent file: %w", err)
+	}
+	return c.AddMagnet(hash)
+}
+
+// WaitForDownload polls the magnet until it is ready or dead.
+func (c *Client) WaitForDownload(torrentID string, maxAttempts int) (*debrid.Torrent, error) {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		t, err := c.GetTorrentInfo(torrentID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch t.Status {
+		case "downloaded":
+			return t, nil
+		case "dead":
+			return nil, fmt.Errorf("torrent failed with status: %s", t.Status)
+		}
+
+		c.logger.Debug().
+			Str("torrent", torrentID).
+			Str("status", t.Status).
+			Int("attempt", attempt+1).
+			Msg("Waiting for download")
+	}
+
+	return nil, fmt.Errorf("timeout waiting for torrent %s", torrentID)
+}
@@ -0,0 +1,137 @@
+// Package breaker implements a consecutive-failure circuit breaker for
+// calls against an upstream that can have prolonged outages (e.g.
+// Real-Debrid), so a bad patch of 5xx responses trips a clear, logged
+// short-circuit instead of quietly flooding the retry queue and burning
+// API quota cycle after cycle.
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned (wrapped) by callers that decline to make a request
+// because the breaker is currently short-circuiting.
+var ErrOpen = errors.New("breaker: circuit open")
+
+// Config tunes a Breaker.
+type Config struct {
+	// FailureThreshold is how many consecutive failures within Window trip
+	// the breaker.
+	FailureThreshold int
+	// Window bounds how stale a failure streak can be before it no longer
+	// counts; a failure followed by a long run of success, then one more
+	// failure much later, shouldn't trip on the old count.
+	Window time.Duration
+	// ResetTimeout is the quiet period after tripping before a single
+	// probe call is let through to test recovery.
+	ResetTimeout time.Duration
+}
+
+// state is the breaker's current disposition.
+type state int
+
+const (
+	closed   state = iota // calls proceed normally
+	open                  // calls are short-circuited
+	halfOpen              // one probe call is in flight
+)
+
+// Breaker short-circuits calls after FailureThreshold consecutive failures,
+// then allows a single probe through every ResetTimeout to test recovery.
+type Breaker struct {
+	cfg Config
+
+	mu          sync.Mutex
+	state       state
+	failures    int
+	lastFailure time.Time
+	openedAt    time.Time
+	probing     bool
+}
+
+// New creates a Breaker from cfg, filling in sane defaults for zero values.
+func New(cfg Config) *Breaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 2 * time.Minute
+	}
+	if cfg.ResetTimeout <= 0 {
+		cfg.ResetTimeout = 30 * time.Second
+	}
+	return &Breaker{cfg: cfg}
+}
+
+// Allow reports whether a call should proceed. Once the breaker has
+// tripped, it returns false until ResetTimeout has elapsed, then returns
+// true exactly once (the probe) until that probe's outcome is recorded.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case closed:
+		return true
+	case halfOpen:
+		return false // a probe is already in flight
+	default: // open
+		if time.Since(b.openedAt) < b.cfg.ResetTimeout {
+			return false
+		}
+		b.state = halfOpen
+		b.probing = true
+		return true
+	}
+}
+
+// RecordSuccess clears the failure streak and closes the breaker. A
+// successful probe call also closes it back up.
+func (b *Breaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	b.state = closed
+}
+
+// RecordFailure counts a failure and trips the breaker once
+// FailureThreshold consecutive failures land within Window. A failed
+// probe re-opens the breaker for another ResetTimeout.
+func (b *Breaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.probing {
+		b.probing = false
+		b.trip()
+		return
+	}
+
+	now := time.Now()
+	if !b.lastFailure.IsZero() && now.Sub(b.lastFailure) > b.cfg.Window {
+		b.failures = 0
+	}
+	b.lastFailure = now
+	b.failures++
+
+	if b.failures >= b.cfg.FailureThreshold {
+		b.trip()
+	}
+}
+
+// trip opens the breaker; callers must hold b.mu.
+func (b *Breaker) trip() {
+	b.state = open
+	b.openedAt = time.Now()
+}
+
+// Open reports whether the breaker is currently short-circuiting calls.
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == open && time.Since(b.openedAt) < b.cfg.ResetTimeout
+}
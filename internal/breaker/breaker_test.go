@@ -0,0 +1,131 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// breaker_test.go guards the closed -> open -> half-open -> closed/open
+// state cycle.
+
+func newTestBreaker() *Breaker {
+	return New(Config{
+		FailureThreshold: 3,
+		Window:           time.Minute,
+		ResetTimeout:     20 * time.Millisecond,
+	})
+}
+
+func TestBreaker_TripsAfterConsecutiveFailuresWithinWindow(t *testing.T) {
+	b := newTestBreaker()
+
+	for i := 0; i < 2; i++ {
+		b.RecordFailure()
+		if !b.Allow() {
+			t.Fatalf("expected breaker to still allow calls before reaching the threshold (failure %d)", i+1)
+		}
+	}
+
+	b.RecordFailure()
+	if b.Allow() {
+		t.Fatalf("expected breaker to trip open after reaching FailureThreshold")
+	}
+}
+
+func TestBreaker_DoesNotTripWhenFailuresSpanOutsideWindow(t *testing.T) {
+	b := New(Config{
+		FailureThreshold: 3,
+		Window:           10 * time.Millisecond,
+		ResetTimeout:     20 * time.Millisecond,
+	})
+
+	b.RecordFailure()
+	b.RecordFailure()
+	time.Sleep(15 * time.Millisecond) // outside Window
+
+	b.RecordFailure()
+	if !b.Allow() {
+		t.Fatalf("expected the stale failure streak to have reset, keeping the breaker closed")
+	}
+}
+
+func TestBreaker_RecordSuccessResetsFailureStreak(t *testing.T) {
+	b := newTestBreaker()
+
+	b.RecordFailure()
+	b.RecordFailure()
+	b.RecordSuccess()
+	b.RecordFailure()
+	b.RecordFailure()
+
+	if !b.Allow() {
+		t.Fatalf("expected RecordSuccess to have cleared the earlier failures, keeping the breaker closed")
+	}
+}
+
+func TestBreaker_AllowsSingleProbeAfterResetTimeoutThenCloses(t *testing.T) {
+	b := newTestBreaker()
+
+	for i := 0; i < 3; i++ {
+		b.RecordFailure()
+	}
+	if b.Allow() {
+		t.Fatalf("expected breaker to be open immediately after tripping")
+	}
+
+	time.Sleep(25 * time.Millisecond)
+
+	if !b.Allow() {
+		t.Fatalf("expected exactly one probe call to be let through after ResetTimeout")
+	}
+	if b.Allow() {
+		t.Fatalf("expected a second concurrent call to be refused while the probe is in flight")
+	}
+
+	b.RecordSuccess()
+	if !b.Allow() {
+		t.Fatalf("expected the breaker to close after a successful probe")
+	}
+}
+
+func TestBreaker_FailedProbeReopensForAnotherResetTimeout(t *testing.T) {
+	b := newTestBreaker()
+
+	for i := 0; i < 3; i++ {
+		b.RecordFailure()
+	}
+	time.Sleep(25 * time.Millisecond)
+
+	if !b.Allow() {
+		t.Fatalf("expected the probe call to be let through")
+	}
+	b.RecordFailure()
+
+	if b.Allow() {
+		t.Fatalf("expected a failed probe to re-open the breaker immediately")
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatalf("expected another probe to be let through after the new ResetTimeout elapses")
+	}
+}
+
+func TestBreaker_Open_ReflectsOnlyWithinResetTimeout(t *testing.T) {
+	b := newTestBreaker()
+
+	for i := 0; i < 3; i++ {
+		b.RecordFailure()
+	}
+	if !b.Open() {
+		t.Fatalf("expected Open to report true right after tripping")
+	}
+
+	// Once ResetTimeout has elapsed, Open reports false even though Allow
+	// hasn't been called to flip the state to half-open yet - Open answers
+	// "is this still short-circuiting on its own", not "has a probe run".
+	time.Sleep(25 * time.Millisecond)
+	if b.Open() {
+		t.Fatalf("expected Open to report false once ResetTimeout has elapsed without a probe")
+	}
+}
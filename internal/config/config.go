@@ -5,15 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sync"
+	"sync/atomic"
 )
 
 // config.go loads, validates, and exposes application configuration.
 
-var (
-	once     sync.Once
-	instance *Config
-)
+// instance is the package singleton, swapped atomically so Watch can
+// publish a reloaded Config while other goroutines call Get concurrently.
+var instance atomic.Pointer[Config]
 
 // Config holds the application configuration
 type Config struct {
@@ -24,23 +23,180 @@ type Config struct {
 	ConcurrentRequests int    `json:"concurrent_requests"`
 	GeneralRateLimit   int    `json:"general_rate_limit"`
 	TorrentsRateLimit  int    `json:"torrents_rate_limit"`
+	DownloadsRateLimit int    `json:"downloads_rate_limit"`
 	WatchMode          bool   `json:"watch_mode"`
 	WatchModeInterval  int    `json:"watch_mode_interval"`
 	RepairTorrents     bool   `json:"repair_torrents"`
+
+	// WatchDir, if set, is watched for dropped .magnet/.torrent files
+	// during Watch mode; each is submitted to Real-Debrid and triggers an
+	// immediate sync cycle instead of waiting for WatchModeInterval.
+	WatchDir string `json:"watch_dir"`
 	MinFileSizeMB      int    `json:"min_file_size_mb"`
 	LogLevel           string `json:"log_level"`
 	PttRename          bool   `json:"ptt_rename"`
 
+	// OrganizerHashAlgorithm enables content-hash tracking ("sha1" or
+	// "xxhash") of organized files; empty disables it.
+	OrganizerHashAlgorithm string `json:"organizer_hash_algorithm"`
+	// OrganizerPlacementMode selects how organized files are placed
+	// relative to their source: "copy" (default), "hardlink", "symlink",
+	// or "reflink".
+	OrganizerPlacementMode string `json:"organizer_placement_mode"`
+
 	// File tracking
 	TrackingFile   string `json:"tracking_file"`
 	FileExpiryDays int    `json:"file_expiry_days"`
 
+	// AuthoritativeDeletePatterns lists filepath.Match glob patterns,
+	// relative to OutputDir, for strm.Service.Watch: a tracked .strm file
+	// that disappears and matches one of these is treated as an
+	// intentional removal (untracked) rather than recreated.
+	AuthoritativeDeletePatterns []string `json:"authoritative_delete_patterns,omitempty"`
+
 	// Retry queue
-	RetryQueueFile   string `json:"retry_queue_file"`
-	MaxRetryAttempts int    `json:"max_retry_attempts"`
+	RetryQueueFile      string `json:"retry_queue_file"`
+	RetryDeadLetterFile string `json:"retry_dead_letter_file"`
+	MaxRetryAttempts    int    `json:"max_retry_attempts"`
+
+	// Disk space guard
+	DiskSpaceMinFreeBytes     int64    `json:"disk_space_min_free_bytes"`
+	DiskSpaceMinFreePercent   float64  `json:"disk_space_min_free_percent"`
+	DiskSpaceCheckIntervalSec int      `json:"disk_space_check_interval_sec"`
+	DiskSpaceBackoffSec       int      `json:"disk_space_backoff_sec"`
+	DiskSpaceMaxBackoffSec    int      `json:"disk_space_max_backoff_sec"`
+	DiskSpaceExtraPaths       []string `json:"disk_space_extra_paths"`
+
+	// TrafficQuota refuses repair reinserts once an account has hit its
+	// hoster traffic allowance (request.TrafficExceededError) this many
+	// times within the window - reinserting a magnet right after a 503
+	// traffic-exceeded response just burns another slot on the same wall.
+	// Zero threshold disables the check.
+	TrafficQuotaThreshold int `json:"traffic_quota_threshold"`
+	TrafficQuotaWindowSec int `json:"traffic_quota_window_sec"`
+
+	// Circuit breaker around Real-Debrid calls; zero fields take the
+	// breaker package's defaults (5 failures / 2m window / 30s reset).
+	CircuitBreakerFailureThreshold int `json:"circuit_breaker_failure_threshold"`
+	CircuitBreakerWindowSec        int `json:"circuit_breaker_window_sec"`
+	CircuitBreakerResetSec         int `json:"circuit_breaker_reset_sec"`
+
+	// STRM output backend
+	Output OutputConfig `json:"output"`
+
+	// OutputFormat selects the on-disk format written per file: "strm"
+	// (default, a single download URL) or "m3u" (a .m3u8 playlist with the
+	// primary URL plus fallback entries, so a player can fail over if the
+	// primary link 404s).
+	OutputFormat string `json:"output_format"`
+
+	// RPC status/control socket; empty disables it
+	RPCSocketPath string `json:"rpc_socket_path"`
+
+	// Prometheus metrics; empty listen address disables the server.
+	// AdminToken additionally gates /debug/queue and the web dashboard's
+	// /api/ routes; empty disables those endpoints.
+	MetricsListenAddr string `json:"metrics_listen_addr"`
+	AdminToken        string `json:"admin_token"`
+
+	// qBittorrent WebUI API shim for *arr apps; empty listen address disables it.
+	QBittorrentListenAddr string `json:"qbittorrent_listen_addr"`
+
+	// WebAddr serves the status/library dashboard described in pkg/web;
+	// empty disables it. Overridable with the --web flag.
+	WebAddr string `json:"web_addr"`
+
+	// Providers lists additional debrid accounts to aggregate alongside
+	// Token's Real-Debrid account. Leave empty to use Real-Debrid alone.
+	Providers []ProviderConfig `json:"providers,omitempty"`
+
+	// Cache mode: materialize real file content under OutputDir via
+	// parallel ranged HTTP fetches instead of writing .strm pointer files
+	CacheMode bool        `json:"cache_mode"`
+	Fetch     FetchConfig `json:"fetch"`
+
+	// PathRemap rewrites STRM output paths and URLs, for migrating a
+	// library between hosts (e.g. Windows to a Linux container) without
+	// regenerating it.
+	PathRemap PathRemapConfig `json:"path_remap"`
 
 	// Internal
-	Path string `json:"-"` // Config file path
+	Path         string `json:"-"` // Directory containing the config file
+	ResolvedPath string `json:"-"` // Full path to the config file Load resolved, for Watch
+}
+
+// FetchConfig tunes the parallel ranged-HTTP downloader used by cache mode.
+type FetchConfig struct {
+	// MaxChunks is the most concurrent range requests issued per file.
+	MaxChunks int `json:"max_chunks"`
+	// ChunkSizeMB is the target size of each range request, in megabytes.
+	ChunkSizeMB int `json:"chunk_size_mb"`
+}
+
+// OutputConfig selects where STRM files are physically written and holds
+// the credentials for non-local backends.
+type OutputConfig struct {
+	// Backend selects the writer: "local" (default), "webdav", "sftp", or "rclone".
+	Backend string `json:"backend"`
+
+	WebDAV WebDAVConfig `json:"webdav"`
+	SFTP   SFTPConfig   `json:"sftp"`
+	Rclone RcloneConfig `json:"rclone"`
+}
+
+// WebDAVConfig configures the WebDAV output backend.
+type WebDAVConfig struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// SFTPConfig configures the SFTP output backend.
+type SFTPConfig struct {
+	Host           string `json:"host"`
+	Port           int    `json:"port"`
+	Username       string `json:"username"`
+	Password       string `json:"password"`
+	PrivateKeyPath string `json:"private_key_path"`
+	BaseDir        string `json:"base_dir"`
+}
+
+// RcloneConfig configures the generic "exec rclone" output backend.
+type RcloneConfig struct {
+	// Remote is the rclone remote:path files are written under, e.g. "gdrive:library".
+	Remote string `json:"remote"`
+	// Binary overrides the rclone executable name; defaults to "rclone".
+	Binary string `json:"binary"`
+}
+
+// PathRemapConfig holds ordered find/replace rules applied to STRM output
+// paths and URLs, plus an optional separator override. It lets a user
+// migrating a Kodi/Plex library between hosts rewrite e.g. "D:\Media\..."
+// to "/mnt/media/..." without regenerating the whole library.
+type PathRemapConfig struct {
+	// Rules are applied in order to both the on-disk relative path and the
+	// URL written inside the .strm file.
+	Rules []PathRemapRule `json:"rules,omitempty"`
+	// PathSeparator overrides the separator used in the on-disk relative
+	// path after Rules are applied; empty keeps the host's native separator.
+	PathSeparator string `json:"path_separator,omitempty"`
+}
+
+// PathRemapRule is one find/replace pair. From is matched literally unless
+// Regex is set, in which case it's compiled as a regular expression and To
+// may reference capture groups (e.g. "$1").
+type PathRemapRule struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Regex bool   `json:"regex,omitempty"`
+}
+
+// ProviderConfig configures one debrid account to aggregate into the
+// organized library. Name selects the backend: "real-debrid", "alldebrid",
+// "premiumize", or "torbox".
+type ProviderConfig struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
 }
 
 // defaults returns a Config with default values
@@ -63,8 +219,14 @@ func defaults() *Config {
 		TrackingFile:   "./cache/file_tracking.json",
 		FileExpiryDays: 6,
 
-		RetryQueueFile:   "./cache/retry_queue.json",
-		MaxRetryAttempts: 3,
+		RetryQueueFile:      "./cache/retry_queue.json",
+		RetryDeadLetterFile: "./cache/retry_queue_dead.json",
+		MaxRetryAttempts:    3,
+
+		Output: OutputConfig{Backend: "local"},
+
+		DownloadsRateLimit: 10,
+		Fetch:              FetchConfig{MaxChunks: 4, ChunkSizeMB: 16},
 	}
 }
 
@@ -105,6 +267,7 @@ func Load(configPath string) (*Config, error) {
 	}
 
 	cfg.Path = filepath.Dir(configFile)
+	cfg.ResolvedPath = configFile
 
 	// Validate
 	if err := cfg.Validate(); err != nil {
@@ -136,23 +299,71 @@ func (c *Config) Validate() error {
 		c.WatchModeInterval = 60
 	}
 
+	if c.Output.Backend == "" {
+		c.Output.Backend = "local"
+	}
+
+	if c.DownloadsRateLimit < 1 {
+		c.DownloadsRateLimit = 10
+	}
+
+	if c.Fetch.MaxChunks < 1 {
+		c.Fetch.MaxChunks = 4
+	}
+
+	if c.Fetch.ChunkSizeMB < 1 {
+		c.Fetch.ChunkSizeMB = 16
+	}
+
 	return nil
 }
 
 // Get returns the singleton config instance
 func Get() *Config {
-	if instance == nil {
-		return defaults()
+	if cfg := instance.Load(); cfg != nil {
+		return cfg
 	}
-	return instance
+	return defaults()
 }
 
 // SetInstance sets the global config instance
 func SetInstance(cfg *Config) {
-	instance = cfg
+	instance.Store(cfg)
 }
 
 // MinFileSizeBytes returns minimum file size in bytes
 func (c *Config) MinFileSizeBytes() int64 {
 	return int64(c.MinFileSizeMB) * 1024 * 1024
 }
+
+// ResolvedProviders returns the debrid accounts to aggregate: Providers
+// verbatim if set, otherwise a single synthesized "real-debrid" entry
+// built from Token, so single-account setups need no config changes.
+func (c *Config) ResolvedProviders() []ProviderConfig {
+	if len(c.Providers) > 0 {
+		return c.Providers
+	}
+	return []ProviderConfig{{Name: "real-debrid", Token: c.Token}}
+}
+
+// DiskSpaceGuardPaths returns the mount paths the disk-space guard should
+// monitor: the STRM output directory, the organizer's source and
+// destination directories, the cache directory, and any configured extra
+// paths. Duplicates are dropped (distinct config fields often resolve to
+// the same mount, or even the same directory) since the guard's Allow
+// would otherwise stat it more than once per check.
+func (c *Config) DiskSpaceGuardPaths() []string {
+	candidates := []string{c.OutputDir, c.Path, c.OrganizedDir, c.CacheDir}
+	candidates = append(candidates, c.DiskSpaceExtraPaths...)
+
+	seen := make(map[string]bool, len(candidates))
+	paths := make([]string, 0, len(candidates))
+	for _, p := range candidates {
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
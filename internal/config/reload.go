@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/rs/zerolog"
+)
+
+// reload.go watches the resolved config file for changes and atomically
+// swaps the package singleton, so long-running commands (watch mode) pick
+// up safe-to-change settings without a restart.
+
+// reloadDebounce coalesces the burst of write events a single editor save
+// tends to produce into one reload.
+const reloadDebounce = 250 * time.Millisecond
+
+// restartRequiredFields names Config fields that are baked into other
+// components at construction time (the Real-Debrid client, output/cache
+// directories); changing them in the running Config would desync from
+// those already-built components, so a reload only warns about them
+// rather than applying the change.
+var restartRequiredFields = []string{"Token", "OutputDir", "CacheDir"}
+
+var (
+	subscribersMu sync.Mutex
+	subscribers   []func(old, new *Config)
+)
+
+// OnChange registers fn to run every time a watched config file is
+// reloaded. fn is called synchronously from the watcher goroutine with the
+// previous and newly swapped-in Config, so it should return quickly.
+func OnChange(fn func(old, new *Config)) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// Watch starts an fsnotify watcher on configPath's parent directory and
+// reloads+swaps the singleton (as returned by Get) on every write or
+// create event for configPath itself, debounced by reloadDebounce. It
+// watches the directory rather than the file because most editors and
+// config-management tools save by writing a temp file and renaming it
+// over the target (the same atomic-save pattern as this repo's own
+// writeFileAtomic in pkg/retry/retry.go) - watching the file directly
+// leaves fsnotify bound to the old, now-deleted inode after the first
+// such save, silently killing hot-reload. It runs until stopCh is closed,
+// so callers should run it in its own goroutine:
+//
+//	go config.Watch(configPath, stopCh)
+func Watch(configPath string, stopCh <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("config: starting watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	dir := filepath.Dir(configPath)
+	name := filepath.Base(configPath)
+
+	if err := watcher.Add(dir); err != nil {
+		return fmt.Errorf("config: watching %s: %w", dir, err)
+	}
+
+	log := logger.New("config")
+	var debounce *time.Timer
+
+	for {
+		select {
+		case <-stopCh:
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Base(event.Name) != name {
+				continue
+			}
+			// Write/Create cover an in-place save; Remove/Rename cover an
+			// atomic save (temp file renamed over configPath) - either
+			// way the directory watch itself stays valid, so just reload.
+			relevant := event.Has(fsnotify.Write) || event.Has(fsnotify.Create) ||
+				event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)
+			if !relevant {
+				continue
+			}
+			if debounce != nil {
+				debounce.Stop()
+			}
+			debounce = time.AfterFunc(reloadDebounce, func() { reload(configPath, log) })
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warn().Err(err).Msg("Config watcher error")
+		}
+	}
+}
+
+// reload re-parses and validates configPath and, on success, atomically
+// swaps it in as the singleton and notifies OnChange subscribers. A config
+// that fails to load or validate is logged and discarded, leaving the
+// previous Config in place.
+func reload(configPath string, log zerolog.Logger) {
+	newCfg, err := Load(configPath)
+	if err != nil {
+		log.Warn().Err(err).Msg("Config reload failed, keeping previous config")
+		return
+	}
+
+	oldCfg := Get()
+	warnRestartRequiredChanges(oldCfg, newCfg, log)
+
+	SetInstance(newCfg)
+	log.Info().Msg("Config reloaded")
+
+	subscribersMu.Lock()
+	subs := append([]func(old, new *Config){}, subscribers...)
+	subscribersMu.Unlock()
+
+	for _, fn := range subs {
+		fn(oldCfg, newCfg)
+	}
+}
+
+// warnRestartRequiredChanges logs a warning for each restartRequiredFields
+// entry whose value differs between old and new, since a reload can't
+// apply those changes to already-constructed components.
+func warnRestartRequiredChanges(old, new *Config, log zerolog.Logger) {
+	oldVal := reflect.ValueOf(old).Elem()
+	newVal := reflect.ValueOf(new).Elem()
+
+	for _, name := range restartRequiredFields {
+		of := oldVal.FieldByName(name)
+		nf := newVal.FieldByName(name)
+		if !of.IsValid() || !nf.IsValid() {
+			continue
+		}
+		if of.Interface() != nf.Interface() {
+			log.Warn().Str("field", name).Msg("Config field changed but requires a restart to take effect")
+		}
+	}
+}
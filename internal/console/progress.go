@@ -6,55 +6,309 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/rs/zerolog"
 )
 
-// progress.go renders terminal progress bars for long-running operations.
+// progress.go renders terminal progress for concurrent operations: a
+// ProgressManager draws one bar per in-flight item plus an aggregate line,
+// redrawn in place via ANSI cursor movement. On non-TTY stderr (e.g. output
+// redirected to a log file) it falls back to periodic log lines instead, so
+// log files aren't filled with carriage-return noise.
+
+// ewmaWeight is the weight given to each new rate sample; 0.2 favors recent
+// throughput while still smoothing out single-sample spikes.
+const ewmaWeight = 0.2
+
+// renderInterval is how often the manager redraws its bars in TTY mode.
+const renderInterval = 100 * time.Millisecond
+
+// logInterval is how often the manager logs aggregate progress in
+// non-interactive (non-TTY) mode.
+const logInterval = 5 * time.Second
+
+// ProgressManager renders multiple concurrent Bars to stderr: one per
+// in-flight operation plus an aggregate summary line.
+type ProgressManager struct {
+	out         io.Writer
+	interactive bool
+	logger      zerolog.Logger
+
+	mu        sync.Mutex
+	bars      []*Bar
+	lastLines int
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// NewProgressManager creates a ProgressManager and starts its background
+// render loop. Call Stop when the manager is no longer needed.
+func NewProgressManager() *ProgressManager {
+	pm := &ProgressManager{
+		out:         os.Stderr,
+		interactive: isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd()),
+		logger:      logger.New("console"),
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+	go pm.run()
+	return pm
+}
 
-// ProgressBar renders a simple single-line progress bar to stdout.
-type ProgressBar struct {
-	label string
-	total int
-	width int
-	out   io.Writer
-	mu    sync.Mutex
+// Stop halts the render loop. Safe to call more than once.
+func (pm *ProgressManager) Stop() {
+	pm.stopOnce.Do(func() { close(pm.stopCh) })
+	<-pm.doneCh
 }
 
-// NewProgressBar creates a new progress bar with the given label and total.
-func NewProgressBar(label string, total int) *ProgressBar {
+// AddBar registers and returns a new Bar tracking total units of work.
+func (pm *ProgressManager) AddBar(label string, total int64) *Bar {
 	if total < 1 {
 		total = 1
 	}
-	return &ProgressBar{
-		label: label,
-		total: total,
-		width: 28,
-		out:   os.Stderr,
+	b := &Bar{
+		label:      label,
+		total:      total,
+		manager:    pm,
+		lastSample: time.Now(),
+	}
+
+	pm.mu.Lock()
+	pm.bars = append(pm.bars, b)
+	pm.mu.Unlock()
+
+	return b
+}
+
+func (pm *ProgressManager) removeBar(target *Bar) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	for i, b := range pm.bars {
+		if b == target {
+			pm.bars = append(pm.bars[:i], pm.bars[i+1:]...)
+			return
+		}
+	}
+}
+
+func (pm *ProgressManager) run() {
+	defer close(pm.doneCh)
+
+	interval := renderInterval
+	if !pm.interactive {
+		interval = logInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pm.stopCh:
+			if pm.interactive {
+				pm.render()
+			}
+			return
+		case <-ticker.C:
+			if pm.interactive {
+				pm.render()
+			} else {
+				pm.logProgress()
+			}
+		}
+	}
+}
+
+// render redraws every active bar plus an aggregate line in place, moving
+// the cursor back up over the previous frame first.
+func (pm *ProgressManager) render() {
+	pm.mu.Lock()
+	bars := make([]*Bar, len(pm.bars))
+	copy(bars, pm.bars)
+	pm.mu.Unlock()
+
+	if pm.lastLines > 0 {
+		fmt.Fprintf(pm.out, "\x1b[%dA", pm.lastLines)
+	}
+
+	var aggCompleted, aggTotal int64
+	var aggRate float64
+	for _, b := range bars {
+		fmt.Fprintf(pm.out, "\x1b[K%s\n", b.render())
+		aggCompleted += atomic.LoadInt64(&b.completed)
+		aggTotal += b.total
+		aggRate += b.rate()
+	}
+
+	eta := etaSeconds(aggCompleted, aggTotal, aggRate)
+	fmt.Fprintf(pm.out, "\x1b[Ktotal: %d/%d  %s/s  eta %s\n",
+		aggCompleted, aggTotal, formatRate(aggRate), formatETA(eta))
+
+	pm.lastLines = len(bars) + 1
+}
+
+// logProgress emits one aggregate log line, used in place of ANSI redraws
+// when stderr isn't a terminal.
+func (pm *ProgressManager) logProgress() {
+	pm.mu.Lock()
+	bars := make([]*Bar, len(pm.bars))
+	copy(bars, pm.bars)
+	pm.mu.Unlock()
+
+	if len(bars) == 0 {
+		return
+	}
+
+	var aggCompleted, aggTotal int64
+	var aggRate float64
+	for _, b := range bars {
+		aggCompleted += atomic.LoadInt64(&b.completed)
+		aggTotal += b.total
+		aggRate += b.rate()
 	}
+
+	pm.logger.Info().
+		Int("active", len(bars)).
+		Int64("completed", aggCompleted).
+		Int64("total", aggTotal).
+		Str("rate", formatRate(aggRate)+"/s").
+		Msg("Transfer progress")
 }
 
-// Update redraws the progress bar with the provided completed count.
-func (p *ProgressBar) Update(completed int) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// Bar tracks progress for a single in-flight operation against total units
+// of work (bytes, or an item count), with an EWMA transfer rate.
+type Bar struct {
+	label   string
+	total   int64
+	manager *ProgressManager
+
+	completed int64 // atomic
+
+	mu             sync.Mutex
+	smoothedRate   float64
+	lastSample     time.Time
+	lastSampleUnit int64
+	done           bool
+}
+
+// Add records n more completed units and refreshes the bar's transfer rate.
+func (b *Bar) Add(n int64) {
+	completed := atomic.AddInt64(&b.completed, n)
+
+	b.mu.Lock()
+	now := time.Now()
+	if elapsed := now.Sub(b.lastSample); elapsed > 0 {
+		sample := float64(completed-b.lastSampleUnit) / elapsed.Seconds()
+		if b.smoothedRate == 0 {
+			b.smoothedRate = sample
+		} else {
+			b.smoothedRate = ewmaWeight*sample + (1-ewmaWeight)*b.smoothedRate
+		}
+		b.lastSample = now
+		b.lastSampleUnit = completed
+	}
+	b.mu.Unlock()
+}
 
-	if completed < 0 {
-		completed = 0
+// Done marks the bar finished and removes it from its manager.
+func (b *Bar) Done() {
+	b.mu.Lock()
+	if b.done {
+		b.mu.Unlock()
+		return
 	}
-	if completed > p.total {
-		completed = p.total
+	b.done = true
+	b.mu.Unlock()
+
+	b.manager.removeBar(b)
+}
+
+func (b *Bar) rate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.smoothedRate
+}
+
+// render formats this bar as a single progress line.
+func (b *Bar) render() string {
+	completed := atomic.LoadInt64(&b.completed)
+	if completed > b.total {
+		completed = b.total
 	}
 
-	percent := float64(completed) / float64(p.total)
-	filled := int(percent * float64(p.width))
-	if filled > p.width {
-		filled = p.width
+	const width = 20
+	percent := float64(completed) / float64(b.total)
+	filled := int(percent * float64(width))
+	if filled > width {
+		filled = width
 	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", width-filled)
 
-	bar := strings.Repeat("=", filled) + strings.Repeat(" ", p.width-filled)
-	percentInt := int(percent * 100.0)
+	rate := b.rate()
+	eta := etaSeconds(completed, b.total, rate)
+
+	return fmt.Sprintf("%-30s [%s] %3d%%  %s/s  eta %s",
+		truncateLabel(b.label, 30), bar, int(percent*100), formatRate(rate), formatETA(eta))
+}
+
+// NewProgressReader wraps r so every Read increments a Bar registered on
+// mgr, suitable for wrapping an HTTP response body or a file reader during
+// a copy. The bar is marked Done once r returns io.EOF or any other error.
+func NewProgressReader(r io.Reader, total int64, label string, mgr *ProgressManager) io.Reader {
+	return &progressReader{r: r, bar: mgr.AddBar(label, total)}
+}
+
+type progressReader struct {
+	r   io.Reader
+	bar *Bar
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	if n > 0 {
+		p.bar.Add(int64(n))
+	}
+	if err != nil {
+		p.bar.Done()
+	}
+	return n, err
+}
+
+func etaSeconds(completed, total int64, rate float64) float64 {
+	if rate <= 0 || completed >= total {
+		return 0
+	}
+	return float64(total-completed) / rate
+}
+
+func formatRate(rate float64) string {
+	switch {
+	case rate >= 1<<20:
+		return fmt.Sprintf("%.1fMB", rate/(1<<20))
+	case rate >= 1<<10:
+		return fmt.Sprintf("%.1fKB", rate/(1<<10))
+	default:
+		return fmt.Sprintf("%.0fB", rate)
+	}
+}
+
+func formatETA(seconds float64) string {
+	if seconds <= 0 {
+		return "-"
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second).String()
+}
 
-	fmt.Fprintf(p.out, "\r%s [%s] %3d%% (%d/%d)", p.label, bar, percentInt, completed, p.total)
-	if completed == p.total {
-		fmt.Fprintln(p.out)
+func truncateLabel(s string, n int) string {
+	if len(s) <= n {
+		return s
 	}
+	return s[:n-1] + "…"
 }
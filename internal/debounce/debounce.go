@@ -0,0 +1,56 @@
+// Package debounce coalesces a burst of events against the same key (a
+// file path, a watch-dir entry) into a single delayed call, so a
+// multi-step file operation (an atomic save, a scraper's batch cleanup)
+// triggers one reaction instead of one per event.
+package debounce
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer delays fn(key) until key has been quiet for delay, resetting
+// the timer on every additional Trigger for the same key.
+type Debouncer struct {
+	mu     sync.Mutex
+	delay  time.Duration
+	fn     func(key string)
+	timers map[string]*time.Timer
+}
+
+// New creates a Debouncer that calls fn(key) delay after the last Trigger
+// for that key.
+func New(delay time.Duration, fn func(key string)) *Debouncer {
+	return &Debouncer{
+		delay:  delay,
+		fn:     fn,
+		timers: make(map[string]*time.Timer),
+	}
+}
+
+// Trigger (re)starts key's delay timer.
+func (d *Debouncer) Trigger(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if t, ok := d.timers[key]; ok {
+		t.Reset(d.delay)
+		return
+	}
+
+	d.timers[key] = time.AfterFunc(d.delay, func() {
+		d.mu.Lock()
+		delete(d.timers, key)
+		d.mu.Unlock()
+		d.fn(key)
+	})
+}
+
+// Stop cancels every pending timer, for a clean shutdown.
+func (d *Debouncer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, t := range d.timers {
+		t.Stop()
+	}
+}
@@ -0,0 +1,78 @@
+// Package debrid defines the provider-neutral interface robofuse talks to
+// debrid services through, so real-debrid, alldebrid, premiumize and
+// torbox accounts can all feed the same organizer/STRM pipeline.
+package debrid
+
+import "time"
+
+// Torrent is a provider-neutral view of a cached torrent.
+type Torrent struct {
+	ID       string
+	Filename string
+	Hash     string
+	Bytes    int64
+	Status   string
+	Progress float64
+	Links    []string
+	Files    []File
+}
+
+// File is a single file within a Torrent.
+type File struct {
+	ID       int
+	Path     string
+	Bytes    int64
+	Selected int
+}
+
+// Download is a provider-neutral view of an unrestricted direct-download
+// link.
+type Download struct {
+	ID         string
+	Filename   string
+	MimeType   string
+	Filesize   int64
+	Link       string
+	Host       string
+	Chunks     int64
+	Download   string
+	Streamable int
+	Generated  time.Time
+}
+
+// IsStreamable reports whether the download can be streamed without a
+// full local download first.
+func (d *Download) IsStreamable() bool {
+	return d.Streamable == 1
+}
+
+// Provider is implemented by every debrid backend robofuse supports.
+// Implementations live in sibling internal packages (one per service) plus
+// an adapter in pkg/realdebrid, so the organizer and STRM generator can
+// aggregate multiple accounts - even across different services - behind
+// one interface.
+type Provider interface {
+	// Name identifies the provider for logs and for the Provider field
+	// stamped onto tracked entries (e.g. "real-debrid", "alldebrid").
+	Name() string
+
+	GetTorrents() (downloaded, dead []*Torrent, err error)
+	GetTorrentInfo(torrentID string) (*Torrent, error)
+	AddMagnet(hash string) (string, error)
+	SelectVideoFiles(torrentID string) (int, error)
+	DeleteTorrent(torrentID string) error
+	WaitForDownload(torrentID string, maxAttempts int) (*Torrent, error)
+	UnrestrictLink(link string) (*Download, error)
+
+	// GetDownloads returns the account's previously-unrestricted download
+	// cache, used to skip re-unrestricting a link that's already been
+	// resolved. Backends with no such persistent cache return (nil, nil);
+	// every link is simply unrestricted fresh on each sync.
+	GetDownloads() ([]*Download, error)
+
+	// AddTorrentFile submits a raw bencoded .torrent file's bytes the same
+	// way AddMagnet submits an infohash, returning the new torrent's ID.
+	// Backends without a native byte-upload endpoint derive the infohash
+	// from data and fall back to AddMagnet.
+	AddTorrentFile(data []byte) (string, error)
+}
@@ -0,0 +1,56 @@
+package debrid
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// registry.go lets each debrid backend register itself by name at init
+// time, so callers can build a Provider from config (provider name + API
+// key) without importing every backend package directly.
+
+// Factory builds a Provider authenticated with apiKey.
+type Factory func(apiKey string) Provider
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Factory)
+)
+
+// Register adds a backend's Factory under name. It panics on a duplicate
+// name, since that can only happen from a programming error at init time.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("debrid: Register called twice for %q", name))
+	}
+	registry[name] = factory
+}
+
+// New builds the Provider registered under name, authenticated with apiKey.
+func New(name, apiKey string) (Provider, error) {
+	mu.RLock()
+	factory, ok := registry[name]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("debrid: unknown provider %q (registered: %v)", name, Registered())
+	}
+	return factory(apiKey), nil
+}
+
+// Registered returns the sorted names of every backend registered so far.
+func Registered() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
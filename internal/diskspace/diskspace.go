@@ -0,0 +1,185 @@
+// Package diskspace guards write-heavy work (STRM generation, retries,
+// unrestrict calls) against running the host filesystem out of space.
+package diskspace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/rs/zerolog"
+)
+
+// diskspace.go implements a periodic free-space check with a blocking Allow
+// gate and a rate-limited warning so callers don't spam logs while paused.
+
+// GuardConfig configures a Guard.
+type GuardConfig struct {
+	// Paths are the mount points to check; the lowest free-space reading wins.
+	Paths []string
+	// MinFreeBytes pauses work when free space on any path drops below it. Zero disables the check.
+	MinFreeBytes int64
+	// MinFreePercent pauses work when free space percentage on any path drops below it. Zero disables the check.
+	MinFreePercent float64
+	// CheckInterval controls how often Allow re-checks while paused.
+	CheckInterval time.Duration
+	// BackoffOnLow is the starting (and floor) time between "still paused"
+	// warning logs; each consecutive warning while still low doubles it,
+	// up to MaxBackoffOnLow.
+	BackoffOnLow time.Duration
+	// MaxBackoffOnLow caps the doubling in BackoffOnLow. Zero defaults to
+	// 16x BackoffOnLow.
+	MaxBackoffOnLow time.Duration
+}
+
+// Guard blocks callers while any configured path is low on free space.
+type Guard struct {
+	cfg    GuardConfig
+	logger zerolog.Logger
+
+	warnMu      sync.Mutex
+	lastWarn    time.Time
+	warnBackoff time.Duration
+
+	paused int32
+}
+
+// NewGuard creates a Guard from cfg. A Guard with no thresholds configured is
+// always a no-op.
+func NewGuard(cfg GuardConfig) *Guard {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 30 * time.Second
+	}
+	if cfg.BackoffOnLow <= 0 {
+		cfg.BackoffOnLow = time.Minute
+	}
+	if cfg.MaxBackoffOnLow <= 0 {
+		cfg.MaxBackoffOnLow = 16 * cfg.BackoffOnLow
+	}
+	return &Guard{cfg: cfg, logger: logger.New("diskspace"), warnBackoff: cfg.BackoffOnLow}
+}
+
+// Enabled reports whether any threshold is configured.
+func (g *Guard) Enabled() bool {
+	return g.cfg.MinFreeBytes > 0 || g.cfg.MinFreePercent > 0
+}
+
+// Paused reports whether the guard is currently blocking callers.
+func (g *Guard) Paused() bool {
+	return atomic.LoadInt32(&g.paused) == 1
+}
+
+// Allow blocks until free space recovers above the configured thresholds on
+// every watched path, or ctx is cancelled. It returns waited=true if it had
+// to block at all, so callers can surface a "disk_paused" indicator. It is a
+// no-op when the guard has no thresholds configured.
+func (g *Guard) Allow(ctx context.Context) (waited bool, err error) {
+	if !g.Enabled() {
+		return false, nil
+	}
+
+	for {
+		if !g.Low() {
+			return waited, nil
+		}
+		waited = true
+
+		select {
+		case <-ctx.Done():
+			return waited, ctx.Err()
+		case <-time.After(g.cfg.CheckInterval):
+		}
+	}
+}
+
+// Low reports whether any configured path is currently below threshold,
+// without blocking. A true result also updates Paused() and emits the
+// rate-limited warning, so both Allow's poll loop and callers that only
+// want to skip new work (rather than wait) share one code path.
+func (g *Guard) Low() bool {
+	if !g.Enabled() {
+		return false
+	}
+
+	low, detail := g.checkLow()
+	if !low {
+		atomic.StoreInt32(&g.paused, 0)
+		g.resetBackoff()
+		return false
+	}
+
+	atomic.StoreInt32(&g.paused, 1)
+	g.warnRateLimited(detail)
+	return true
+}
+
+// checkLow evaluates all configured paths and reports whether any is below threshold.
+func (g *Guard) checkLow() (bool, string) {
+	for _, path := range g.cfg.Paths {
+		if path == "" {
+			continue
+		}
+
+		free, total, err := diskUsage(path)
+		if err != nil {
+			g.logger.Debug().Err(err).Str("path", path).Msg("Failed to stat disk usage")
+			continue
+		}
+
+		if g.cfg.MinFreeBytes > 0 && free < g.cfg.MinFreeBytes {
+			return true, fmt.Sprintf("%s: %d bytes free, need %d", path, free, g.cfg.MinFreeBytes)
+		}
+
+		if g.cfg.MinFreePercent > 0 && total > 0 {
+			percent := float64(free) / float64(total) * 100
+			if percent < g.cfg.MinFreePercent {
+				return true, fmt.Sprintf("%s: %.1f%% free, need %.1f%%", path, percent, g.cfg.MinFreePercent)
+			}
+		}
+	}
+	return false, ""
+}
+
+// warnRateLimited logs a low-disk-space warning at most once per
+// warnBackoff interval, doubling warnBackoff (up to MaxBackoffOnLow) each
+// time it actually warns so a prolonged low-space spell doesn't spam logs
+// at a fixed rate. resetBackoff puts it back to BackoffOnLow once free
+// space recovers.
+func (g *Guard) warnRateLimited(detail string) {
+	g.warnMu.Lock()
+	defer g.warnMu.Unlock()
+
+	if time.Since(g.lastWarn) < g.warnBackoff {
+		return
+	}
+	g.lastWarn = time.Now()
+	g.logger.Warn().Str("detail", detail).Dur("next_warn_in", g.warnBackoff).Msg("Disk space low, pausing work until it recovers")
+
+	g.warnBackoff *= 2
+	if g.warnBackoff > g.cfg.MaxBackoffOnLow {
+		g.warnBackoff = g.cfg.MaxBackoffOnLow
+	}
+}
+
+// resetBackoff restores warnBackoff to its starting value once free space
+// has recovered.
+func (g *Guard) resetBackoff() {
+	g.warnMu.Lock()
+	defer g.warnMu.Unlock()
+	g.warnBackoff = g.cfg.BackoffOnLow
+}
+
+// diskUsage returns free and total bytes for the filesystem containing path.
+func diskUsage(path string) (free int64, total int64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	free = int64(stat.Bavail) * int64(stat.Bsize)
+	total = int64(stat.Blocks) * int64(stat.Bsize)
+	return free, total, nil
+}
@@ -0,0 +1,86 @@
+// Package premiumize implements debrid.Provider against the Premiumize.me
+// HTTP API (https://www.premiumize.me/api).
+package premiumize
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/internal/request"
+	"github.com/rs/zerolog"
+)
+
+const defaultHost = "https://www.premiumize.me/api"
+
+func init() {
+	debrid.Register("premiumize", func(apiKey string) debrid.Provider {
+		return New(apiKey)
+	})
+}
+
+// Client is the Premiumize API client.
+type Client struct {
+	host   string
+	apiKey string
+	client *request.Client
+	logger zerolog.Logger
+}
+
+// New creates a new Premiumize client authenticated with apiKey.
+func New(apiKey string) *Client {
+	return &Client{
+		host:   defaultHost,
+		apiKey: apiKey,
+		client: request.New(
+			request.WithMaxRetries(5),
+			request.WithRetryableStatus(429, 502, 503),
+		),
+		logger: logger.New("premiumize"),
+	}
+}
+
+// Name identifies this provider as "premiumize".
+func (c *Client) Name() string {
+	return "premiumize"
+}
+
+// call performs a GET against path with the given form values plus the
+// shared apikey param, and unmarshals the response into out. Premiumize
+// reports failures as {"status":"error","message":"..."} rather than a
+// distinct HTTP status, so callers must check Status themselves.
+func (c *Client) call(path string, form url.Values, out any) error {
+	if form == nil {
+		form = url.Values{}
+	}
+	form.Set("apikey", c.apiKey)
+
+	req, err := http.NewRequest(http.MethodPost, c.host+path, nil)
+	if err != nil {
+		return err
+	}
+	req.URL.RawQuery = form.Encode()
+
+	body, err := c.client.MakeRequest(req)
+	if err != nil {
+		return err
+	}
+
+	var status struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	if status.Status == "error" {
+		return fmt.Errorf("premiumize: %s", status.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
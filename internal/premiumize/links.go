@@ -0,0 +1,46 @@
+package premiumize
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+)
+
+// UnrestrictLink implements debrid.Provider. Premiumize directly exposes
+// direct-download links on already-cached items, so this re-resolves link
+// through /transfer/directdl to get a fresh, streamable URL.
+func (c *Client) UnrestrictLink(link string) (*debrid.Download, error) {
+	var data struct {
+		Content []struct {
+			Path   string `json:"path"`
+			Size   int64  `json:"size"`
+			Link   string `json:"link"`
+			Stream string `json:"stream_link,omitempty"`
+		} `json:"content"`
+	}
+	if err := c.call("/transfer/directdl", url.Values{"src": {link}}, &data); err != nil {
+		return nil, fmt.Errorf("resolving direct link: %w", err)
+	}
+	if len(data.Content) == 0 {
+		return nil, fmt.Errorf("premiumize: no direct link for %s", link)
+	}
+
+	item := data.Content[0]
+	streamable := 0
+	download := item.Link
+	if item.Stream != "" {
+		streamable = 1
+		download = item.Stream
+	}
+
+	return &debrid.Download{
+		Filename:   item.Path,
+		Filesize:   item.Size,
+		Link:       link,
+		Download:   download,
+		Streamable: streamable,
+		Generated:  time.Now(),
+	}, nil
+}
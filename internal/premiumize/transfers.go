@@ -0,0 +1,190 @@
+package premiumize
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/pkg/torrentfile"
+)
+
+// transfer mirrors one entry of GET /transfer/list's "transfers" array.
+type transfer struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Status   string  `json:"status"`
+	Progress float64 `json:"progress"`
+	FolderID string  `json:"folder_id"`
+	FileID   string  `json:"file_id"`
+	Size     int64   `json:"size"`
+}
+
+func (t transfer) toTorrent() *debrid.Torrent {
+	status := "downloading"
+	switch t.Status {
+	case "finished":
+		status = "downloaded"
+	case "error", "deleted":
+		status = "dead"
+	}
+	return &debrid.Torrent{
+		ID:       t.ID,
+		Filename: t.Name,
+		Bytes:    t.Size,
+		Status:   status,
+		Progress: t.Progress * 100,
+	}
+}
+
+// AddMagnet creates a transfer from a magnet built out of hash and returns
+// its Premiumize transfer ID.
+func (c *Client) AddMagnet(hash string) (string, error) {
+	magnet := fmt.Sprintf("magnet:?xt=urn:btih:%s", hash)
+
+	var data struct {
+		ID string `json:"id"`
+	}
+	form := url.Values{"src": {magnet}}
+	if err := c.call("/transfer/create", form, &data); err != nil {
+		return "", fmt.Errorf("creating transfer: %w", err)
+	}
+
+	c.logger.Info().Str("id", data.ID).Msg("Added magnet")
+	return data.ID, nil
+}
+
+// GetTorrents implements debrid.Provider.
+func (c *Client) GetTorrents() ([]*debrid.Torrent, []*debrid.Torrent, error) {
+	var data struct {
+		Transfers []transfer `json:"transfers"`
+	}
+	if err := c.call("/transfer/list", nil, &data); err != nil {
+		return nil, nil, fmt.Errorf("listing transfers: %w", err)
+	}
+
+	var downloaded, dead []*debrid.Torrent
+	for _, t := range data.Transfers {
+		dt := t.toTorrent()
+		switch dt.Status {
+		case "downloaded":
+			downloaded = append(downloaded, dt)
+		case "dead":
+			dead = append(dead, dt)
+		}
+	}
+	return downloaded, dead, nil
+}
+
+// GetTorrentInfo finds torrentID among the account's transfers and fills
+// in its folder contents as Files.
+func (c *Client) GetTorrentInfo(torrentID string) (*debrid.Torrent, error) {
+	var data struct {
+		Transfers []transfer `json:"transfers"`
+	}
+	if err := c.call("/transfer/list", nil, &data); err != nil {
+		return nil, fmt.Errorf("listing transfers: %w", err)
+	}
+
+	for _, t := range data.Transfers {
+		if t.ID != torrentID {
+			continue
+		}
+		dt := t.toTorrent()
+		if t.FolderID != "" {
+			files, err := c.folderFiles(t.FolderID)
+			if err != nil {
+				return nil, fmt.Errorf("listing folder %s: %w", t.FolderID, err)
+			}
+			dt.Files = files
+		}
+		return dt, nil
+	}
+	return nil, fmt.Errorf("premiumize: transfer %s not found", torrentID)
+}
+
+// folderFiles lists the files under a Premiumize folder ID.
+func (c *Client) folderFiles(folderID string) ([]debrid.File, error) {
+	var data struct {
+		Content []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			Size int64  `json:"size"`
+			Type string `json:"type"`
+			Link string `json:"link"`
+		} `json:"content"`
+	}
+	if err := c.call("/folder/list", url.Values{"id": {folderID}}, &data); err != nil {
+		return nil, err
+	}
+
+	var files []debrid.File
+	for i, item := range data.Content {
+		if item.Type != "file" {
+			continue
+		}
+		files = append(files, debrid.File{ID: i, Path: item.Name, Bytes: item.Size, Selected: 1})
+	}
+	return files, nil
+}
+
+// SelectVideoFiles is a no-op for Premiumize: transfers have no
+// file-selection step, so this just reports how many files are available.
+func (c *Client) SelectVideoFiles(torrentID string) (int, error) {
+	info, err := c.GetTorrentInfo(torrentID)
+	if err != nil {
+		return 0, err
+	}
+	return len(info.Files), nil
+}
+
+// DeleteTorrent implements debrid.Provider.
+func (c *Client) DeleteTorrent(torrentID string) error {
+	if err := c.call("/transfer/delete", url.Values{"id": {torrentID}}, nil); err != nil {
+		return fmt.Errorf("deleting transfer: %w", err)
+	}
+	c.logger.Info().Str("id", torrentID).Msg("Deleted torrent")
+	return nil
+}
+
+// GetDownloads implements debrid.Provider. Premiumize has no persistent
+// unrestricted-link cache, so every link is simply unrestricted fresh on
+// each sync.
+func (c *Client) GetDownloads() ([]*debrid.Download, error) {
+	return nil, nil
+}
+
+// AddTorrentFile implements debrid.Provider. Premiumize has no native
+// byte-upload endpoint, so this derives the infohash from data and falls
+// back to AddMagnet.
+func (c *Client) AddTorrentFile(data []byte) (string, error) {
+	hash, _, _, err := torrentfile.ParseTorrentBytes(data)
+	if err != nil {
+		return "", fmt.Errorf("parsing torrent file: %w", err)
+	}
+	return c.AddMagnet(hash)
+}
+
+// WaitForDownload polls the transfer until it finishes or errors.
+func (c *Client) WaitForDownload(torrentID string, maxAttempts int) (*debrid.Torrent, error) {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		t, err := c.GetTorrentInfo(torrentID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch t.Status {
+		case "downloaded":
+			return t, nil
+		case "dead":
+			return nil, fmt.Errorf("torrent failed with status: %s", t.Status)
+		}
+
+		c.logger.Debug().
+			Str("torrent", torrentID).
+			Str("status", t.Status).
+			Int("attempt", attempt+1).
+			Msg("Waiting for download")
+	}
+
+	return nil, fmt.Errorf("timeout waiting for torrent %s", torrentID)
+}
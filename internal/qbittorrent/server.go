@@ -0,0 +1,300 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/pkg/realdebrid"
+	"github.com/rs/zerolog"
+)
+
+// server.go implements just enough of the qBittorrent WebUI API v2 for
+// *arr apps (Sonarr/Radarr/Prowlarr) to use robofuse as their download
+// client: login, add a magnet, and poll its status/files/properties.
+// Every call is mapped onto the existing realdebrid.Client.
+
+// waitAttempts bounds how long the background goroutine spawned by
+// torrents/add polls Real-Debrid before giving up on a torrent.
+const waitAttempts = 60
+
+// Server serves the qBittorrent WebUI API subset backed by rd and store.
+type Server struct {
+	addr   string
+	rd     *realdebrid.Client
+	store  *Store
+	logger zerolog.Logger
+}
+
+// NewServer creates a Server that will listen on addr once started.
+func NewServer(addr string, rd *realdebrid.Client, store *Store) *Server {
+	return &Server{
+		addr:   addr,
+		rd:     rd,
+		store:  store,
+		logger: logger.New("qbittorrent"),
+	}
+}
+
+// ListenAndServe serves until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/auth/login", s.handleLogin)
+	mux.HandleFunc("/api/v2/app/version", s.handleVersion)
+	mux.HandleFunc("/api/v2/torrents/add", s.handleAdd)
+	mux.HandleFunc("/api/v2/torrents/info", s.handleInfo)
+	mux.HandleFunc("/api/v2/torrents/delete", s.handleDelete)
+	mux.HandleFunc("/api/v2/torrents/properties", s.handleProperties)
+	mux.HandleFunc("/api/v2/torrents/files", s.handleFiles)
+
+	srv := &http.Server{Addr: s.addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	s.logger.Info().Str("addr", s.addr).Msg("qBittorrent shim listening")
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handleLogin accepts any credentials; robofuse has no separate
+// authentication layer for the shim, so it just satisfies the handshake
+// *arr apps expect before they start polling.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: "SID", Value: "robofuse"})
+	fmt.Fprint(w, "Ok.")
+}
+
+// handleVersion reports a qBittorrent version new enough for *arr apps'
+// feature checks.
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, "v4.6.0")
+}
+
+// handleAdd accepts a magnet link via the "urls" field and an optional
+// "category", then adds it to Real-Debrid and tracks it under its
+// infohash so later info/properties/files/delete calls can find it.
+// Real-Debrid processing (file selection, waiting for completion) happens
+// in the background; the call returns as soon as the magnet is queued,
+// matching qBittorrent's own fire-and-forget behavior.
+func (s *Server) handleAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+	}
+
+	category := r.FormValue("category")
+
+	urls := strings.Fields(r.FormValue("urls"))
+	if len(urls) == 0 {
+		http.Error(w, "no urls provided", http.StatusBadRequest)
+		return
+	}
+
+	for _, magnet := range urls {
+		hash, err := magnetHash(magnet)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("url", magnet).Msg("Rejecting torrents/add entry")
+			continue
+		}
+
+		rdID, err := s.rd.AddMagnet(hash)
+		if err != nil {
+			s.logger.Error().Err(err).Str("hash", hash).Msg("Failed to add magnet")
+			continue
+		}
+
+		if err := s.store.Set(hash, rdID, category); err != nil {
+			s.logger.Error().Err(err).Str("hash", hash).Msg("Failed to persist qBittorrent entry")
+		}
+
+		go func(rdID, hash string) {
+			if _, err := s.rd.WaitForDownload(rdID, waitAttempts); err != nil {
+				s.logger.Warn().Err(err).Str("hash", hash).Msg("Torrent did not finish downloading")
+			}
+		}(rdID, hash)
+	}
+
+	fmt.Fprint(w, "Ok.")
+}
+
+// handleInfo answers GET /api/v2/torrents/info with one entry per tracked
+// infohash, optionally filtered by the "hashes" (pipe-separated) or
+// "category" query params.
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	wantHashes := filterSet(r.URL.Query().Get("hashes"))
+	wantCategory := r.URL.Query().Get("category")
+
+	var result []torrentInfo
+	for hash, entry := range s.store.All() {
+		if wantHashes != nil && !wantHashes[hash] {
+			continue
+		}
+		if wantCategory != "" && entry.Category != wantCategory {
+			continue
+		}
+
+		info, err := s.rd.GetTorrentInfo(entry.RDID)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("hash", hash).Msg("Failed to fetch torrent info")
+			continue
+		}
+
+		result = append(result, torrentInfo{
+			Hash:         hash,
+			Name:         info.Filename,
+			Size:         info.Bytes,
+			Progress:     info.Progress / 100,
+			DlSpeed:      info.Speed,
+			State:        mapStatus(info.Status),
+			Category:     entry.Category,
+			SavePath:     entry.Category,
+			ContentPath:  info.Filename,
+			AddedOn:      entry.AddedAt.Unix(),
+			CompletionOn: entry.AddedAt.Unix(),
+			Downloaded:   info.Bytes,
+			NumSeeds:     0,
+		})
+	}
+
+	writeJSON(w, result)
+}
+
+// handleDelete answers POST /api/v2/torrents/delete for the "hashes"
+// (pipe-separated) form field, removing the torrent from Real-Debrid and
+// the local store.
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	for hash := range filterSet(r.FormValue("hashes")) {
+		entry, ok := s.store.Get(hash)
+		if !ok {
+			continue
+		}
+		if err := s.rd.DeleteTorrent(entry.RDID); err != nil {
+			s.logger.Warn().Err(err).Str("hash", hash).Msg("Failed to delete torrent on Real-Debrid")
+		}
+		if err := s.store.Delete(hash); err != nil {
+			s.logger.Warn().Err(err).Str("hash", hash).Msg("Failed to remove qBittorrent entry")
+		}
+	}
+
+	fmt.Fprint(w, "Ok.")
+}
+
+// handleProperties answers GET /api/v2/torrents/properties?hash=....
+func (s *Server) handleProperties(w http.ResponseWriter, r *http.Request) {
+	entry, info, ok := s.lookup(w, r.URL.Query().Get("hash"))
+	if !ok {
+		return
+	}
+
+	writeJSON(w, torrentProperties{
+		SavePath:       entry.Category,
+		AdditionDate:   entry.AddedAt.Unix(),
+		CompletionDate: entry.AddedAt.Unix(),
+		TotalSize:      info.Bytes,
+		Seeds:          0,
+		DlSpeed:        info.Speed,
+	})
+}
+
+// handleFiles answers GET /api/v2/torrents/files?hash=....
+func (s *Server) handleFiles(w http.ResponseWriter, r *http.Request) {
+	_, info, ok := s.lookup(w, r.URL.Query().Get("hash"))
+	if !ok {
+		return
+	}
+
+	progress := 0.0
+	if info.Status == "downloaded" {
+		progress = 1
+	}
+
+	files := make([]torrentFile, len(info.Files))
+	for i, f := range info.Files {
+		files[i] = torrentFile{
+			Index:    i,
+			Name:     f.Path,
+			Size:     f.Bytes,
+			Progress: progress,
+			Priority: f.Selected,
+		}
+	}
+
+	writeJSON(w, files)
+}
+
+// lookup resolves hash to its tracked entry and current Real-Debrid
+// torrent info, writing the appropriate HTTP error and returning ok=false
+// if either step fails.
+func (s *Server) lookup(w http.ResponseWriter, hash string) (*Entry, *realdebrid.TorrentInfo, bool) {
+	entry, ok := s.store.Get(hash)
+	if !ok {
+		http.Error(w, "torrent not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+
+	info, err := s.rd.GetTorrentInfo(entry.RDID)
+	if err != nil {
+		http.Error(w, "torrent not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+
+	return entry, info, true
+}
+
+// magnetHash extracts the infohash from a magnet URI's xt=urn:btih: parameter.
+func magnetHash(magnet string) (string, error) {
+	u, err := url.Parse(magnet)
+	if err != nil || u.Scheme != "magnet" {
+		return "", fmt.Errorf("not a magnet URI")
+	}
+
+	for _, xt := range u.Query()["xt"] {
+		if hash, ok := strings.CutPrefix(xt, "urn:btih:"); ok {
+			return strings.ToLower(hash), nil
+		}
+	}
+
+	return "", fmt.Errorf("magnet URI has no btih hash")
+}
+
+// filterSet splits a pipe-separated qBittorrent hash list into a lookup
+// set. It returns nil for "" or "all", meaning "no filter".
+func filterSet(raw string) map[string]bool {
+	if raw == "" || raw == "all" {
+		return nil
+	}
+
+	set := make(map[string]bool)
+	for _, h := range strings.Split(raw, "|") {
+		set[h] = true
+	}
+	return set
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,28 @@
+package qbittorrent
+
+// status.go translates Real-Debrid torrent statuses into the state
+// vocabulary qBittorrent's WebUI API reports, so *arr apps can drive their
+// usual "is it done yet" polling loop against robofuse unmodified.
+
+// mapStatus maps a Real-Debrid torrent status to its closest qBittorrent
+// WebUI API state.
+func mapStatus(rdStatus string) string {
+	switch rdStatus {
+	case "downloaded":
+		return "pausedUP"
+	case "downloading":
+		return "downloading"
+	case "queued":
+		return "queuedDL"
+	case "waiting_files_selection":
+		return "stalledDL"
+	case "magnet_conversion", "magnet_error":
+		return "metaDL"
+	case "compressing", "uploading":
+		return "stalledUP"
+	case "virus", "error", "dead":
+		return "error"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,124 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/rs/zerolog"
+)
+
+// store.go persists the infohash -> Real-Debrid torrent ID / category
+// mapping the shim needs to answer *arr apps' torrents/info and
+// torrents/properties calls, and for the organizer to route output into
+// a per-category subfolder of OrganizedDir.
+
+// Entry tracks the Real-Debrid torrent and caller-supplied category
+// behind a single infohash exposed to *arr apps as a qBittorrent torrent.
+type Entry struct {
+	RDID     string    `json:"rd_id"`
+	Category string    `json:"category"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// Store maps the infohashes *arr apps know about to Real-Debrid torrent
+// IDs and categories, persisted as JSON under the cache directory.
+type Store struct {
+	path   string
+	mu     sync.RWMutex
+	data   map[string]*Entry
+	logger zerolog.Logger
+}
+
+// NewStore creates a Store backed by path, loading any existing data.
+func NewStore(path string) *Store {
+	s := &Store{
+		path:   path,
+		data:   make(map[string]*Entry),
+		logger: logger.New("qbittorrent"),
+	}
+
+	if err := s.load(); err != nil {
+		s.logger.Debug().Err(err).Msg("No existing qBittorrent store, starting fresh")
+	}
+
+	return s
+}
+
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.data)
+}
+
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// Set records the Real-Debrid torrent ID and category for hash.
+func (s *Store) Set(hash, rdID, category string) error {
+	s.mu.Lock()
+	s.data[hash] = &Entry{RDID: rdID, Category: category, AddedAt: time.Now()}
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// Get returns the entry recorded for hash, if any.
+func (s *Store) Get(hash string) (*Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.data[hash]
+	return e, ok
+}
+
+// Delete removes the entry recorded for hash.
+func (s *Store) Delete(hash string) error {
+	s.mu.Lock()
+	delete(s.data, hash)
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// CategoryFor returns the category recorded for the entry whose RDID
+// matches rdID, or "" if none is tracked.
+func (s *Store) CategoryFor(rdID string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, e := range s.data {
+		if e.RDID == rdID {
+			return e.Category
+		}
+	}
+	return ""
+}
+
+// All returns a snapshot copy of every tracked entry, keyed by infohash.
+func (s *Store) All() map[string]*Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string]*Entry, len(s.data))
+	for hash, e := range s.data {
+		cp := *e
+		out[hash] = &cp
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package qbittorrent
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// store_test.go guards the infohash <-> category round-trip the organizer
+// relies on to route output into per-category subfolders.
+
+func TestStore_SetGetDelete(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "qbittorrent_store.json"))
+
+	if err := store.Set("abc123", "rd1", "tv-sonarr"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	entry, ok := store.Get("abc123")
+	if !ok {
+		t.Fatal("expected entry to be found")
+	}
+	if entry.RDID != "rd1" || entry.Category != "tv-sonarr" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+
+	if got := store.CategoryFor("rd1"); got != "tv-sonarr" {
+		t.Fatalf("CategoryFor: expected tv-sonarr, got %q", got)
+	}
+
+	if err := store.Delete("abc123"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := store.Get("abc123"); ok {
+		t.Fatal("expected entry to be gone after Delete")
+	}
+}
+
+func TestMagnetHash(t *testing.T) {
+	cases := []struct {
+		magnet  string
+		want    string
+		wantErr bool
+	}{
+		{"magnet:?xt=urn:btih:ABCDEF0123456789&dn=Some+Movie", "abcdef0123456789", false},
+		{"https://example.com/not-a-magnet", "", true},
+		{"magnet:?dn=no-hash", "", true},
+	}
+
+	for _, c := range cases {
+		got, err := magnetHash(c.magnet)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("magnetHash(%q): expected error, got %q", c.magnet, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("magnetHash(%q): unexpected error: %v", c.magnet, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("magnetHash(%q) = %q, want %q", c.magnet, got, c.want)
+		}
+	}
+}
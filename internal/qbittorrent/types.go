@@ -0,0 +1,41 @@
+package qbittorrent
+
+// types.go models the subset of qBittorrent WebUI API v2 JSON responses
+// robofuse needs to satisfy *arr apps' download-client polling.
+
+// torrentInfo is the shape of one entry in GET /api/v2/torrents/info.
+type torrentInfo struct {
+	Hash         string  `json:"hash"`
+	Name         string  `json:"name"`
+	Size         int64   `json:"size"`
+	Progress     float64 `json:"progress"`
+	DlSpeed      int64   `json:"dlspeed"`
+	State        string  `json:"state"`
+	Category     string  `json:"category"`
+	SavePath     string  `json:"save_path"`
+	ContentPath  string  `json:"content_path"`
+	AddedOn      int64   `json:"added_on"`
+	CompletionOn int64   `json:"completion_on"`
+	AmountLeft   int64   `json:"amount_left"`
+	Downloaded   int64   `json:"downloaded"`
+	NumSeeds     int     `json:"num_seeds"`
+}
+
+// torrentProperties is the shape of GET /api/v2/torrents/properties.
+type torrentProperties struct {
+	SavePath       string `json:"save_path"`
+	AdditionDate   int64  `json:"addition_date"`
+	CompletionDate int64  `json:"completion_date"`
+	TotalSize      int64  `json:"total_size"`
+	Seeds          int    `json:"seeds"`
+	DlSpeed        int64  `json:"dl_speed"`
+}
+
+// torrentFile is the shape of one entry in GET /api/v2/torrents/files.
+type torrentFile struct {
+	Index    int     `json:"index"`
+	Name     string  `json:"name"`
+	Size     int64   `json:"size"`
+	Progress float64 `json:"progress"`
+	Priority int     `json:"priority"`
+}
@@ -0,0 +1,70 @@
+// Package quota tracks how often a debrid account has recently hit its
+// hoster traffic allowance (request.TrafficExceededError), so repair can
+// refuse to reinsert a torrent while the account is still over that
+// ceiling instead of immediately burning another magnet slot on the same
+// wall.
+package quota
+
+import (
+	"sync"
+	"time"
+)
+
+// Config tunes a Tracker.
+type Config struct {
+	// Threshold is how many TrafficExceededErrors within Window trip the
+	// ceiling. Zero disables the check.
+	Threshold int
+	// Window bounds how stale a traffic-exceeded hit can be before it no
+	// longer counts toward Threshold.
+	Window time.Duration
+}
+
+// Tracker counts recent traffic-exceeded hits in a sliding window.
+type Tracker struct {
+	cfg Config
+
+	mu   sync.Mutex
+	hits []time.Time
+}
+
+// New creates a Tracker from cfg, filling in a sane default Window for a
+// zero value. A Tracker with Threshold <= 0 never reports Exceeded.
+func New(cfg Config) *Tracker {
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Minute
+	}
+	return &Tracker{cfg: cfg}
+}
+
+// RecordExceeded records a traffic-exceeded hit.
+func (t *Tracker) RecordExceeded() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.hits = append(t.prune(), time.Now())
+}
+
+// Exceeded reports whether Threshold traffic-exceeded hits have landed
+// within Window.
+func (t *Tracker) Exceeded() bool {
+	if t.cfg.Threshold <= 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.hits = t.prune()
+	return len(t.hits) >= t.cfg.Threshold
+}
+
+// prune drops hits older than Window; callers must hold t.mu.
+func (t *Tracker) prune() []time.Time {
+	cutoff := time.Now().Add(-t.cfg.Window)
+	fresh := t.hits[:0]
+	for _, h := range t.hits {
+		if h.After(cutoff) {
+			fresh = append(fresh, h)
+		}
+	}
+	return fresh
+}
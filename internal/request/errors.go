@@ -0,0 +1,36 @@
+package request
+
+import "errors"
+
+// errors.go defines sentinel and structured errors shared by API clients built on Client.
+
+// HTTPError represents an HTTP error response with a status code and optional
+// provider-specific error code.
+type HTTPError struct {
+	StatusCode int
+	Message    string
+	Code       string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Code != "" {
+		return e.Message + " (" + e.Code + ")"
+	}
+	return e.Message
+}
+
+// Sentinel errors returned by debrid provider clients for conditions callers
+// need to distinguish with errors.Is.
+var (
+	// TorrentNotFoundError indicates the requested torrent no longer exists.
+	TorrentNotFoundError = errors.New("torrent not found")
+
+	// HosterUnavailableError indicates the file/hoster is unavailable or has been removed.
+	HosterUnavailableError = errors.New("hoster unavailable")
+
+	// TrafficExceededError indicates the account has exceeded its traffic allowance for a hoster.
+	TrafficExceededError = errors.New("traffic exceeded")
+
+	// ErrLinkBroken indicates a previously valid link is no longer accessible.
+	ErrLinkBroken = errors.New("link broken")
+)
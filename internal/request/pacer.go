@@ -0,0 +1,144 @@
+package request
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// pacer.go implements an adaptive rate limiter modeled on rclone's mailru
+// pacer: instead of a fixed token-bucket rate, the client paces itself by a
+// single sleep interval that grows multiplicatively on server-signaled
+// throttling (429/503, Retry-After) and decays exponentially back toward
+// minSleep on success. This lets a client find the server's real limit
+// instead of hammering a fixed rate until 429s pile up.
+
+// Pacer paces requests by a sleep interval that adapts to server responses.
+// It is safe for concurrent use.
+type Pacer struct {
+	mu         sync.Mutex
+	minSleep   time.Duration
+	maxSleep   time.Duration
+	decayConst time.Duration
+	sleep      time.Duration
+}
+
+// NewPacer creates a Pacer starting at minSleep. decayConst is the
+// approximate time constant of the exponential decay back to minSleep after
+// a burst of throttling: roughly decayConst of sustained success halves the
+// distance from maxSleep back to minSleep.
+func NewPacer(minSleep, maxSleep, decayConst time.Duration) *Pacer {
+	return &Pacer{
+		minSleep:   minSleep,
+		maxSleep:   maxSleep,
+		decayConst: decayConst,
+		sleep:      minSleep,
+	}
+}
+
+// Wait blocks for the current sleep interval, or until ctx is done.
+func (p *Pacer) Wait(ctx context.Context) error {
+	p.mu.Lock()
+	sleep := p.sleep
+	p.mu.Unlock()
+
+	if sleep <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(sleep):
+		return nil
+	}
+}
+
+// Update adjusts the pacer's sleep interval based on a completed request.
+// A 429/503 response (or any response carrying a Retry-After header) grows
+// the sleep interval; any other successful response decays it back down.
+func (p *Pacer) Update(resp *http.Response, err error) {
+	if err != nil || resp == nil {
+		return
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		p.grow(retryAfter(resp))
+		return
+	}
+
+	if retryAfter := retryAfter(resp); retryAfter > 0 {
+		p.grow(retryAfter)
+		return
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		p.decay()
+	}
+}
+
+// grow doubles the sleep interval (or jumps straight to floor, if it is
+// larger than double the current sleep), clamped to maxSleep.
+func (p *Pacer) grow(floor time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	next := p.sleep * 2
+	if floor > next {
+		next = floor
+	}
+	if next > p.maxSleep {
+		next = p.maxSleep
+	}
+	p.sleep = next
+}
+
+// decay shrinks the sleep interval exponentially back toward minSleep, with
+// decayConst as the time constant of that decay.
+func (p *Pacer) decay() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.sleep <= p.minSleep {
+		p.sleep = p.minSleep
+		return
+	}
+
+	steps := float64(p.decayConst) / float64(p.minSleep)
+	if steps < 1 {
+		steps = 1
+	}
+
+	above := float64(p.sleep - p.minSleep)
+	above *= math.Exp(-1 / steps)
+
+	p.sleep = p.minSleep + time.Duration(above)
+}
+
+// retryAfter parses a Retry-After header in either of its two HTTP forms
+// (delta-seconds or an HTTP-date) and returns the resulting wait duration,
+// or 0 if the header is absent or unparsable.
+func retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if when, err := http.ParseTime(v); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
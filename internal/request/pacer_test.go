@@ -0,0 +1,118 @@
+package request
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// pacer_test.go guards Pacer's grow/decay curve, including the steps<1
+// floor decay hits when decayConst is small relative to minSleep.
+
+func respWithStatus(code int) *http.Response {
+	return &http.Response{StatusCode: code, Header: make(http.Header)}
+}
+
+func respWithRetryAfter(code, seconds int) *http.Response {
+	resp := respWithStatus(code)
+	resp.Header.Set("Retry-After", strconv.Itoa(seconds))
+	return resp
+}
+
+func TestPacer_Update_GrowsOnThrottleStatus(t *testing.T) {
+	p := NewPacer(100*time.Millisecond, 1*time.Second, 500*time.Millisecond)
+
+	p.Update(respWithStatus(http.StatusTooManyRequests), nil)
+	if p.sleep != 200*time.Millisecond {
+		t.Fatalf("expected sleep doubled to 200ms, got %v", p.sleep)
+	}
+
+	p.Update(respWithStatus(http.StatusServiceUnavailable), nil)
+	if p.sleep != 400*time.Millisecond {
+		t.Fatalf("expected sleep doubled to 400ms, got %v", p.sleep)
+	}
+}
+
+func TestPacer_Update_RetryAfterJumpsPastDouble(t *testing.T) {
+	p := NewPacer(100*time.Millisecond, 10*time.Second, 500*time.Millisecond)
+
+	// Retry-After of 5s dwarfs double the current 100ms sleep, so grow
+	// should jump straight to the floor instead of just doubling.
+	p.Update(respWithRetryAfter(http.StatusTooManyRequests, 5), nil)
+	if p.sleep != 5*time.Second {
+		t.Fatalf("expected sleep to jump to the 5s floor, got %v", p.sleep)
+	}
+}
+
+func TestPacer_Update_GrowClampsAtMaxSleep(t *testing.T) {
+	p := NewPacer(100*time.Millisecond, 1*time.Second, 500*time.Millisecond)
+
+	p.Update(respWithRetryAfter(http.StatusTooManyRequests, 30), nil)
+	if p.sleep != p.maxSleep {
+		t.Fatalf("expected sleep clamped to maxSleep %v, got %v", p.maxSleep, p.sleep)
+	}
+}
+
+func TestPacer_Update_DecaysOnSuccess(t *testing.T) {
+	p := NewPacer(100*time.Millisecond, 10*time.Second, 2*time.Second)
+	p.sleep = 1 * time.Second
+
+	p.Update(respWithStatus(http.StatusOK), nil)
+
+	// steps = decayConst/minSleep = 20, so one decay step should only
+	// shave a small fraction off the distance above minSleep.
+	above := float64(1*time.Second - p.minSleep)
+	wantAbove := above * math.Exp(-1.0/20)
+	want := p.minSleep + time.Duration(wantAbove)
+
+	if p.sleep != want {
+		t.Fatalf("expected sleep %v after one decay step, got %v", want, p.sleep)
+	}
+	if p.sleep <= p.minSleep {
+		t.Fatalf("expected sleep still above minSleep after a single decay step, got %v", p.sleep)
+	}
+}
+
+func TestPacer_Decay_FloorsStepsAtOne(t *testing.T) {
+	// decayConst much smaller than minSleep would otherwise compute
+	// steps < 1, which the code floors to 1 rather than letting the
+	// exponent blow up past its intended range.
+	p := NewPacer(1*time.Second, 10*time.Second, 1*time.Millisecond)
+	p.sleep = 3 * time.Second
+
+	p.decay()
+
+	above := float64(3*time.Second - p.minSleep)
+	want := p.minSleep + time.Duration(above*math.Exp(-1))
+	if p.sleep != want {
+		t.Fatalf("expected steps to floor at 1, giving sleep %v, got %v", want, p.sleep)
+	}
+}
+
+func TestPacer_Decay_SettlesAtMinSleep(t *testing.T) {
+	p := NewPacer(100*time.Millisecond, 10*time.Second, 2*time.Second)
+	p.sleep = p.minSleep
+
+	p.decay()
+
+	if p.sleep != p.minSleep {
+		t.Fatalf("expected sleep to stay at minSleep, got %v", p.sleep)
+	}
+}
+
+func TestPacer_Update_IgnoresErrorsAndNilResponse(t *testing.T) {
+	p := NewPacer(100*time.Millisecond, 10*time.Second, 2*time.Second)
+
+	p.Update(nil, errors.New("request failed"))
+	if p.sleep != p.minSleep {
+		t.Fatalf("expected an error response to leave sleep unchanged, got %v", p.sleep)
+	}
+
+	p.Update(nil, nil)
+	if p.sleep != p.minSleep {
+		t.Fatalf("expected a nil response to leave sleep unchanged, got %v", p.sleep)
+	}
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/robofuse/robofuse/internal/logger"
 	"github.com/rs/zerolog"
+	"golang.org/x/net/http2"
 	"golang.org/x/net/proxy"
 	"golang.org/x/time/rate"
 )
@@ -50,6 +51,7 @@ type ClientOption func(*Client)
 type Client struct {
 	client          *http.Client
 	rateLimiter     *rate.Limiter
+	pacer           *Pacer
 	headers         map[string]string
 	headersMu       sync.RWMutex
 	maxRetries      int
@@ -58,6 +60,17 @@ type Client struct {
 	retryableStatus map[int]struct{}
 	logger          zerolog.Logger
 	proxy           string
+
+	http2Enabled        bool
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	dialTimeout         time.Duration
+
+	// endpointName labels the latency/rate-limit-wait observations below
+	// (e.g. "general", "torrents", "downloads").
+	endpointName      string
+	latencyObserver   func(endpoint string, seconds float64)
+	rateLimitObserver func(endpoint string, seconds float64)
 }
 
 // WithMaxRetries sets the maximum number of retry attempts
@@ -81,6 +94,16 @@ func WithRateLimiter(rl *rate.Limiter) ClientOption {
 	}
 }
 
+// WithPacer replaces the fixed rate.Limiter with an adaptive Pacer that
+// grows its sleep interval on server-signaled throttling (Retry-After,
+// 429/503) and decays it back toward minSleep on success. When set, it
+// supersedes WithRateLimiter.
+func WithPacer(minSleep, maxSleep, decayConst time.Duration) ClientOption {
+	return func(c *Client) {
+		c.pacer = NewPacer(minSleep, maxSleep, decayConst)
+	}
+}
+
 // WithHeaders sets default headers
 func WithHeaders(headers map[string]string) ClientOption {
 	return func(c *Client) {
@@ -114,6 +137,66 @@ func WithProxy(proxyURL string) ClientOption {
 	}
 }
 
+// WithHTTP2 enables or disables HTTP/2 on the underlying transport.
+// Disabled by default: some debrid providers' edge servers misbehave on
+// HTTP/2, and it used to be the only way to get there pre-Pacer. Enable
+// it for hosts that benefit from request multiplexing, e.g. a burst of
+// small metadata lookups against the same host.
+func WithHTTP2(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.http2Enabled = enabled
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the transport's MaxIdleConnsPerHost, so a
+// client hammering a single host with many small requests can keep more
+// than the Go default of 2 connections warm.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(c *Client) {
+		c.maxIdleConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept in the
+// transport's pool before being closed.
+func WithIdleConnTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.idleConnTimeout = d
+	}
+}
+
+// WithDialTimeout sets the timeout for establishing new connections,
+// including through the SOCKS5 proxy dialer.
+func WithDialTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.dialTimeout = d
+	}
+}
+
+// WithEndpointName labels this client's latency/rate-limit-wait observations
+// (e.g. "general", "torrents", "downloads").
+func WithEndpointName(name string) ClientOption {
+	return func(c *Client) {
+		c.endpointName = name
+	}
+}
+
+// WithLatencyObserver registers a callback invoked with the endpoint name
+// and request duration (in seconds) after every underlying HTTP round trip.
+func WithLatencyObserver(fn func(endpoint string, seconds float64)) ClientOption {
+	return func(c *Client) {
+		c.latencyObserver = fn
+	}
+}
+
+// WithRateLimitObserver registers a callback invoked with the endpoint name
+// and time spent waiting on the rate limiter, in seconds.
+func WithRateLimitObserver(fn func(endpoint string, seconds float64)) ClientOption {
+	return func(c *Client) {
+		c.rateLimitObserver = fn
+	}
+}
+
 // SetHeader sets a header value
 func (c *Client) SetHeader(key, value string) {
 	c.headersMu.Lock()
@@ -121,16 +204,38 @@ func (c *Client) SetHeader(key, value string) {
 	c.headersMu.Unlock()
 }
 
+// CloseIdleConnections closes any connections in the transport's idle
+// pool, e.g. after rotating a proxy so stale connections aren't reused.
+func (c *Client) CloseIdleConnections() {
+	c.client.CloseIdleConnections()
+}
+
 // doRequest performs a single HTTP request with rate limiting
 func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
-	if c.rateLimiter != nil {
-		err := c.rateLimiter.Wait(req.Context())
-		if err != nil {
-			return nil, fmt.Errorf("rate limiter wait: %w", err)
-		}
+	waitStart := time.Now()
+	var waitErr error
+	switch {
+	case c.pacer != nil:
+		waitErr = c.pacer.Wait(req.Context())
+	case c.rateLimiter != nil:
+		waitErr = c.rateLimiter.Wait(req.Context())
+	}
+	if c.rateLimitObserver != nil {
+		c.rateLimitObserver(c.endpointName, time.Since(waitStart).Seconds())
+	}
+	if waitErr != nil {
+		return nil, fmt.Errorf("rate limiter wait: %w", waitErr)
 	}
 
-	return c.client.Do(req)
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if c.latencyObserver != nil {
+		c.latencyObserver(c.endpointName, time.Since(start).Seconds())
+	}
+	if c.pacer != nil {
+		c.pacer.Update(resp, err)
+	}
+	return resp, err
 }
 
 // Do performs an HTTP request with retries for certain status codes
@@ -267,8 +372,19 @@ func New(options ...ClientOption) *Client {
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: client.skipTLSVerify,
 			},
-			DisableKeepAlives: false,
-			TLSNextProto:      make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
+			DisableKeepAlives:   false,
+			MaxIdleConnsPerHost: client.maxIdleConnsPerHost,
+			IdleConnTimeout:     client.idleConnTimeout,
+		}
+
+		dialer := &net.Dialer{Timeout: client.dialTimeout}
+
+		if client.http2Enabled {
+			if err := http2.ConfigureTransport(transport); err != nil {
+				client.logger.Error().Msgf("Failed to configure HTTP/2 transport: %v", err)
+			}
+		} else {
+			transport.TLSNextProto = make(map[string]func(authority string, c *tls.Conn) http.RoundTripper)
 		}
 
 		if client.proxy != "" {
@@ -284,12 +400,14 @@ func New(options ...ClientOption) *Client {
 						auth.Password = password
 					}
 
-					dialer, err := proxy.SOCKS5("tcp", socksURL.Host, auth, proxy.Direct)
+					socksDialer, err := proxy.SOCKS5("tcp", socksURL.Host, auth, dialer)
 					if err != nil {
 						client.logger.Error().Msgf("Failed to create SOCKS5 dialer: %v", err)
+					} else if ctxDialer, ok := socksDialer.(proxy.ContextDialer); ok {
+						transport.DialContext = ctxDialer.DialContext
 					} else {
 						transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
-							return dialer.Dial(network, addr)
+							return socksDialer.Dial(network, addr)
 						}
 					}
 				}
@@ -305,6 +423,10 @@ func New(options ...ClientOption) *Client {
 			transport.Proxy = http.ProxyFromEnvironment
 		}
 
+		if transport.DialContext == nil {
+			transport.DialContext = dialer.DialContext
+		}
+
 		client.client.Transport = transport
 	}
 
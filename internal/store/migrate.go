@@ -0,0 +1,56 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"go.etcd.io/bbolt"
+)
+
+// migrate.go one-shot-imports a legacy JSON "database" file into a bucket
+// the first time robofuse runs against the bbolt store, then moves the
+// JSON file aside so it's never mistaken for the live database again.
+
+// MigrateJSONFile imports jsonPath's top-level object into bucket, one key
+// per entry, if bucket is still empty. On success it renames jsonPath to
+// jsonPath+".bak" and returns the number of entries imported. A missing
+// jsonPath, or a bucket that's already populated, is not an error - both
+// just mean there's nothing to migrate.
+func MigrateJSONFile(db *DB, jsonPath, bucket string) (int, error) {
+	if db.Count(bucket) > 0 {
+		return 0, nil
+	}
+
+	data, err := os.ReadFile(jsonPath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("store: reading %s: %w", jsonPath, err)
+	}
+
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return 0, fmt.Errorf("store: parsing %s: %w", jsonPath, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		b := Bucket(tx, bucket)
+		for key, raw := range entries {
+			if err := b.Put([]byte(key), raw); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("store: importing %s: %w", jsonPath, err)
+	}
+
+	if err := os.Rename(jsonPath, jsonPath+".bak"); err != nil {
+		return len(entries), fmt.Errorf("store: archiving %s: %w", jsonPath, err)
+	}
+
+	return len(entries), nil
+}
@@ -0,0 +1,139 @@
+// Package store provides a small bbolt-backed key/value layer shared by
+// robofuse's various on-disk "databases" (file tracking, organizer state,
+// torrent/download bookkeeping). It replaces the old pattern of loading a
+// JSON file wholesale into a map and rewriting it in full on every change,
+// which risked corrupting the file on a crash mid-write and grew slower as
+// a library's file count grew.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// Bucket names for the state robofuse persists. Open creates all of them
+// up front so callers never have to handle a missing-bucket error.
+const (
+	TrackingBucket  = "tracking"
+	OrganizedBucket = "organized"
+	TorrentsBucket  = "torrents"
+	DownloadsBucket = "downloads"
+)
+
+var buckets = []string{TrackingBucket, OrganizedBucket, TorrentsBucket, DownloadsBucket}
+
+// DB is a typed, bucketed wrapper around a single bbolt database file.
+type DB struct {
+	bolt *bbolt.DB
+}
+
+// Open opens (creating if necessary) a bbolt database at path, along with
+// the fixed set of buckets robofuse's stores use.
+func Open(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("store: creating cache dir: %w", err)
+	}
+
+	bolt, err := bbolt.Open(path, 0644, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("store: opening %s: %w", path, err)
+	}
+
+	err = bolt.Update(func(tx *bbolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		bolt.Close()
+		return nil, fmt.Errorf("store: creating buckets: %w", err)
+	}
+
+	return &DB{bolt: bolt}, nil
+}
+
+// Close closes the underlying database file.
+func (d *DB) Close() error {
+	return d.bolt.Close()
+}
+
+// Update runs fn in a read-write transaction, for callers that need to
+// touch more than one bucket (or bucket and key) atomically. Use Bucket to
+// look up a bucket by name inside fn.
+func (d *DB) Update(fn func(tx *bbolt.Tx) error) error {
+	return d.bolt.Update(fn)
+}
+
+// View runs fn in a read-only transaction.
+func (d *DB) View(fn func(tx *bbolt.Tx) error) error {
+	return d.bolt.View(fn)
+}
+
+// Bucket looks up a named bucket within tx. The bucket is guaranteed to
+// exist for any of the names declared above, since Open creates them all.
+func Bucket(tx *bbolt.Tx, name string) *bbolt.Bucket {
+	return tx.Bucket([]byte(name))
+}
+
+// Put JSON-encodes v and stores it under key in bucket.
+func (d *DB) Put(bucket, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("store: encoding %s/%s: %w", bucket, key, err)
+	}
+	return d.Update(func(tx *bbolt.Tx) error {
+		return Bucket(tx, bucket).Put([]byte(key), data)
+	})
+}
+
+// Get decodes the value stored under key in bucket into v, reporting
+// whether an entry was found.
+func (d *DB) Get(bucket, key string, v any) (bool, error) {
+	var found bool
+	err := d.View(func(tx *bbolt.Tx) error {
+		data := Bucket(tx, bucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, v)
+	})
+	return found, err
+}
+
+// Delete removes key from bucket. Deleting a missing key is not an error.
+func (d *DB) Delete(bucket, key string) error {
+	return d.Update(func(tx *bbolt.Tx) error {
+		return Bucket(tx, bucket).Delete([]byte(key))
+	})
+}
+
+// ForEach streams every key and raw JSON value in bucket to fn, without
+// unmarshalling the whole bucket into memory first. The byte slice passed
+// to fn is only valid for the duration of the call, per bbolt's Cursor
+// semantics, so fn must copy it if it needs to keep it.
+func (d *DB) ForEach(bucket string, fn func(key string, raw []byte) error) error {
+	return d.View(func(tx *bbolt.Tx) error {
+		return Bucket(tx, bucket).ForEach(func(k, v []byte) error {
+			return fn(string(k), v)
+		})
+	})
+}
+
+// Count returns the number of entries in bucket.
+func (d *DB) Count(bucket string) int {
+	var n int
+	d.View(func(tx *bbolt.Tx) error {
+		n = Bucket(tx, bucket).Stats().KeyN
+		return nil
+	})
+	return n
+}
@@ -0,0 +1,78 @@
+// Package torbox implements debrid.Provider against the TorBox HTTP API
+// (https://api.torbox.app).
+package torbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/internal/request"
+	"github.com/rs/zerolog"
+)
+
+const defaultHost = "https://api.torbox.app/v1/api"
+
+func init() {
+	debrid.Register("torbox", func(apiKey string) debrid.Provider {
+		return New(apiKey)
+	})
+}
+
+// Client is the TorBox API client.
+type Client struct {
+	host   string
+	apiKey string
+	client *request.Client
+	logger zerolog.Logger
+}
+
+// New creates a new TorBox client authenticated with apiKey.
+func New(apiKey string) *Client {
+	return &Client{
+		host:   defaultHost,
+		apiKey: apiKey,
+		client: request.New(
+			request.WithHeaders(map[string]string{
+				"Authorization": fmt.Sprintf("Bearer %s", apiKey),
+			}),
+			request.WithMaxRetries(5),
+			request.WithRetryableStatus(429, 502, 503),
+		),
+		logger: logger.New("torbox"),
+	}
+}
+
+// Name identifies this provider as "torbox".
+func (c *Client) Name() string {
+	return "torbox"
+}
+
+// apiResponse is the envelope every TorBox endpoint responds with.
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Detail  string          `json:"detail"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// do performs req and unmarshals the "data" envelope into out.
+func (c *Client) do(req *http.Request, out any) error {
+	body, err := c.client.MakeRequest(req)
+	if err != nil {
+		return err
+	}
+
+	var resp apiResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("torbox: %s", resp.Detail)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, out)
+}
@@ -0,0 +1,43 @@
+package torbox
+
+import (
+	"fmt"
+	"net/http"
+	gourl "net/url"
+	"strings"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+)
+
+// UnrestrictLink implements debrid.Provider. link is expected to be
+// "torrentID:fileID", matching the pair TorBox's requestdl endpoint needs;
+// there is no separate unrestrict step once a torrent is cached.
+func (c *Client) UnrestrictLink(link string) (*debrid.Download, error) {
+	torrentID, fileID, ok := strings.Cut(link, ":")
+	if !ok {
+		return nil, fmt.Errorf("torbox: malformed link %q, expected torrentID:fileID", link)
+	}
+
+	q := gourl.Values{
+		"token":      {c.apiKey},
+		"torrent_id": {torrentID},
+		"file_id":    {fileID},
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/torrents/requestdl?%s", c.host, q.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var downloadURL string
+	if err := c.do(req, &downloadURL); err != nil {
+		return nil, fmt.Errorf("requesting download link: %w", err)
+	}
+
+	return &debrid.Download{
+		Link:       link,
+		Download:   downloadURL,
+		Streamable: 1,
+		Generated:  time.Now(),
+	}, nil
+}
@@ -0,0 +1,184 @@
+package torbox
+
+import (
+	"fmt"
+	"net/http"
+	gourl "net/url"
+	"strconv"
+	"strings"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/pkg/torrentfile"
+)
+
+// torrentEntry mirrors one entry of GET /torrents/mylist's "data" array.
+type torrentEntry struct {
+	ID          int          `json:"id"`
+	Name        string       `json:"name"`
+	Hash        string       `json:"hash"`
+	Size        int64        `json:"size"`
+	DownloadFin bool         `json:"download_finished"`
+	DownloadPct float64      `json:"progress"`
+	Files       []torboxFile `json:"files"`
+}
+
+type torboxFile struct {
+	ID   int    `json:"id"`
+	Name string `json:"short_name"`
+	Size int64  `json:"size"`
+}
+
+func (t torrentEntry) toTorrent() *debrid.Torrent {
+	status := "downloading"
+	if t.DownloadFin {
+		status = "downloaded"
+	}
+
+	files := make([]debrid.File, len(t.Files))
+	for i, f := range t.Files {
+		files[i] = debrid.File{ID: f.ID, Path: f.Name, Bytes: f.Size, Selected: 1}
+	}
+
+	return &debrid.Torrent{
+		ID:       strconv.Itoa(t.ID),
+		Filename: t.Name,
+		Hash:     t.Hash,
+		Bytes:    t.Size,
+		Status:   status,
+		Progress: t.DownloadPct * 100,
+		Files:    files,
+	}
+}
+
+// AddMagnet creates a torrent from a magnet built out of hash and returns
+// its TorBox torrent ID.
+func (c *Client) AddMagnet(hash string) (string, error) {
+	magnet := fmt.Sprintf("magnet:?xt=urn:btih:%s", hash)
+
+	form := gourl.Values{"magnet": {magnet}}
+	req, err := http.NewRequest(http.MethodPost, c.host+"/torrents/createtorrent", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var data struct {
+		TorrentID int `json:"torrent_id"`
+	}
+	if err := c.do(req, &data); err != nil {
+		return "", fmt.Errorf("creating torrent: %w", err)
+	}
+
+	c.logger.Info().Int("id", data.TorrentID).Msg("Added magnet")
+	return strconv.Itoa(data.TorrentID), nil
+}
+
+// listTorrents fetches the account's full torrent list.
+func (c *Client) listTorrents() ([]torrentEntry, error) {
+	req, err := http.NewRequest(http.MethodGet, c.host+"/torrents/mylist?bypass_cache=true", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []torrentEntry
+	if err := c.do(req, &data); err != nil {
+		return nil, fmt.Errorf("listing torrents: %w", err)
+	}
+	return data, nil
+}
+
+// GetTorrents implements debrid.Provider.
+func (c *Client) GetTorrents() ([]*debrid.Torrent, []*debrid.Torrent, error) {
+	entries, err := c.listTorrents()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var downloaded []*debrid.Torrent
+	for _, e := range entries {
+		if e.DownloadFin {
+			downloaded = append(downloaded, e.toTorrent())
+		}
+	}
+	return downloaded, nil, nil
+}
+
+// GetTorrentInfo implements debrid.Provider.
+func (c *Client) GetTorrentInfo(torrentID string) (*debrid.Torrent, error) {
+	entries, err := c.listTorrents()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if strconv.Itoa(e.ID) == torrentID {
+			return e.toTorrent(), nil
+		}
+	}
+	return nil, fmt.Errorf("torbox: torrent %s not found", torrentID)
+}
+
+// SelectVideoFiles is a no-op for TorBox: every file in a torrent becomes
+// downloadable once the torrent is cached, so this just reports the
+// number of files available.
+func (c *Client) SelectVideoFiles(torrentID string) (int, error) {
+	info, err := c.GetTorrentInfo(torrentID)
+	if err != nil {
+		return 0, err
+	}
+	return len(info.Files), nil
+}
+
+// DeleteTorrent implements debrid.Provider.
+func (c *Client) DeleteTorrent(torrentID string) error {
+	form := gourl.Values{"torrent_id": {torrentID}, "operation": {"delete"}}
+	req, err := http.NewRequest(http.MethodPost, c.host+"/torrents/controltorrent", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := c.do(req, nil); err != nil {
+		return fmt.Errorf("deleting torrent: %w", err)
+	}
+	c.logger.Info().Str("id", torrentID).Msg("Deleted torrent")
+	return nil
+}
+
+// GetDownloads implements debrid.Provider. TorBox has no persistent
+// unrestricted-link cache, so every link is simply unrestricted fresh on
+// each sync.
+func (c *Client) GetDownloads() ([]*debrid.Download, error) {
+	return nil, nil
+}
+
+// AddTorrentFile implements debrid.Provider. TorBox has no native
+// byte-upload endpoint, so this derives the infohash from data and falls
+// back to AddMagnet.
+func (c *Client) AddTorrentFile(data []byte) (string, error) {
+	hash, _, _, err := torrentfile.ParseTorrentBytes(data)
+	if err != nil {
+		return "", fmt.Errorf("parsing torrent file: %w", err)
+	}
+	return c.AddMagnet(hash)
+}
+
+// WaitForDownload polls the torrent until it finishes.
+func (c *Client) WaitForDownload(torrentID string, maxAttempts int) (*debrid.Torrent, error) {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		t, err := c.GetTorrentInfo(torrentID)
+		if err != nil {
+			return nil, err
+		}
+		if t.Status == "downloaded" {
+			return t, nil
+		}
+
+		c.logger.Debug().
+			Str("torrent", torrentID).
+			Str("status", t.Status).
+			Int("attempt", attempt+1).
+			Msg("Waiting for download")
+	}
+
+	return nil, fmt.Errorf("timeout waiting for torrent %s", torrentID)
+}
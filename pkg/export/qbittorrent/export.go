@@ -0,0 +1,158 @@
+// Package qbittorrent exports robofuse's tracked torrents as a qBittorrent
+// BT_backup directory (one <infohash>.torrent + <infohash>.fastresume pair
+// per torrent), so a user can point a self-hosted qBittorrent instance (or
+// seedbox) at the already-organized library and resume seeding without
+// re-downloading anything.
+package qbittorrent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/robofuse/robofuse/internal/config"
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/pkg/tracking"
+	"github.com/rs/zerolog"
+)
+
+// ProviderLookup resolves a tracked torrent's provider name (e.g.
+// "real-debrid", "alldebrid") to the account that produced it, so a
+// torrent tracked under a non-primary account is fetched from the right
+// place instead of always hitting the primary one.
+type ProviderLookup func(name string) (debrid.Provider, bool)
+
+// Service builds a qBittorrent BT_backup export from robofuse's tracked
+// torrents.
+type Service struct {
+	config      *config.Config
+	providerFor ProviderLookup
+	tracking    *tracking.Service
+	logger      zerolog.Logger
+}
+
+// New creates an export Service. providerFor resolves a torrent's recorded
+// provider name to the account that fetches its file list and infohash;
+// trackingSvc supplies the set of torrent IDs currently tracked.
+func New(cfg *config.Config, providerFor ProviderLookup, trackingSvc *tracking.Service) *Service {
+	return &Service{
+		config:      cfg,
+		providerFor: providerFor,
+		tracking:    trackingSvc,
+		logger:      logger.New("export-qbittorrent"),
+	}
+}
+
+// Options configures a single Export call.
+type Options struct {
+	// DestDir is the BT_backup directory the .torrent/.fastresume pairs are
+	// written into.
+	DestDir string
+	// ReplaceFrom/ReplaceTo rewrite the save_path prefix robofuse's
+	// OrganizedDir resolves to, for when the new host mounts the library at
+	// a different path (e.g. a seedbox mapping /mnt/library differently).
+	ReplaceFrom string
+	ReplaceTo   string
+}
+
+// Result summarizes one Export call.
+type Result struct {
+	Exported int
+	Skipped  int
+	Errors   []string
+}
+
+// Export writes one .torrent/.fastresume pair per distinct TorrentID found
+// in the tracking store.
+func (s *Service) Export(opts Options) (*Result, error) {
+	if err := os.MkdirAll(opts.DestDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating destination directory: %w", err)
+	}
+
+	torrentIDs := s.distinctTorrentIDs()
+	result := &Result{}
+
+	for torrentID, provider := range torrentIDs {
+		if err := s.exportTorrent(torrentID, provider, opts); err != nil {
+			s.logger.Warn().Err(err).Str("torrent_id", torrentID).Msg("Failed to export torrent")
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", torrentID, err))
+			result.Skipped++
+			continue
+		}
+		result.Exported++
+	}
+
+	return result, nil
+}
+
+// distinctTorrentIDs maps every tracked torrent ID to the provider that
+// produced it (the provider of whichever tracked file is seen last for
+// that torrent, which is fine since a torrent isn't expected to straddle
+// providers).
+func (s *Service) distinctTorrentIDs() map[string]string {
+	ids := make(map[string]string)
+	for _, tr := range s.tracking.All() {
+		if tr.TorrentID == "" {
+			continue
+		}
+		ids[tr.TorrentID] = tr.Provider
+	}
+	return ids
+}
+
+func (s *Service) exportTorrent(torrentID, provider string, opts Options) error {
+	providerName := provider
+	if providerName == "" {
+		providerName = "real-debrid"
+	}
+	p, ok := s.providerFor(providerName)
+	if !ok {
+		return fmt.Errorf("provider %q is no longer configured", providerName)
+	}
+
+	info, err := p.GetTorrentInfo(torrentID)
+	if err != nil {
+		return fmt.Errorf("fetching torrent info: %w", err)
+	}
+	if info.Hash == "" {
+		return fmt.Errorf("torrent has no infohash")
+	}
+
+	savePath := filepath.Join(s.config.OrganizedDir, sanitizeComponent(info.Filename))
+	if opts.ReplaceFrom != "" {
+		savePath = strings.Replace(savePath, opts.ReplaceFrom, opts.ReplaceTo, 1)
+	}
+
+	category := sanitizeComponent(info.Filename)
+	var tags []string
+	if provider != "" {
+		tags = []string{provider}
+	}
+
+	torrentBytes, err := buildTorrentFile(info)
+	if err != nil {
+		return fmt.Errorf("building .torrent: %w", err)
+	}
+	fastresumeBytes, err := buildFastresume(info, savePath, category, tags, s.config.MinFileSizeBytes())
+	if err != nil {
+		return fmt.Errorf("building .fastresume: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(opts.DestDir, info.Hash+".torrent"), torrentBytes, 0644); err != nil {
+		return fmt.Errorf("writing .torrent: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(opts.DestDir, info.Hash+".fastresume"), fastresumeBytes, 0644); err != nil {
+		return fmt.Errorf("writing .fastresume: %w", err)
+	}
+
+	return nil
+}
+
+// sanitizeComponent makes a torrent filename safe to use as a single path
+// component / category name.
+func sanitizeComponent(name string) string {
+	replacer := strings.NewReplacer("/", "_", "\\", "_", ":", "_", "\x00", "")
+	return strings.TrimSpace(replacer.Replace(name))
+}
@@ -0,0 +1,311 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/console"
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/internal/request"
+	"github.com/robofuse/robofuse/pkg/progress"
+	"github.com/rs/zerolog"
+)
+
+// fetch.go downloads a file in parallel HTTP range-request chunks for hosts
+// that advertise Range support, falling back to a single streamed GET
+// otherwise. Per-chunk retries and rate limiting are inherited from the
+// caller-supplied request.Client, matching how the rest of the repo shares
+// its HTTP clients rather than rolling its own retry loop.
+
+// Fetcher downloads files using a shared rate-limited, retrying HTTP client.
+type Fetcher struct {
+	client    *request.Client
+	maxChunks int
+	chunkSize int64
+	progress  *progress.Hub
+	console   *console.ProgressManager
+	logger    zerolog.Logger
+}
+
+// New creates a Fetcher. maxChunks and chunkSize fall back to sane defaults
+// when zero or negative. hub may be nil to disable progress publishing.
+func New(client *request.Client, maxChunks int, chunkSize int64, hub *progress.Hub) *Fetcher {
+	if maxChunks < 1 {
+		maxChunks = 4
+	}
+	if chunkSize < 1 {
+		chunkSize = 16 * 1024 * 1024
+	}
+
+	return &Fetcher{
+		client:    client,
+		maxChunks: maxChunks,
+		chunkSize: chunkSize,
+		progress:  hub,
+		logger:    logger.New("fetch"),
+	}
+}
+
+// SetConsole attaches a ProgressManager so every subsequent Fetch also
+// renders a local terminal bar (or periodic log line on non-TTY output)
+// alongside any progress.Hub publishing. Pass nil to disable it again.
+func (f *Fetcher) SetConsole(mgr *console.ProgressManager) {
+	f.console = mgr
+}
+
+type byteRange struct {
+	start, end int64 // inclusive
+}
+
+// planChunks splits [0, size) into up to maxChunks ranges of roughly
+// chunkSize bytes each, growing the chunk size rather than the chunk count
+// once maxChunks would otherwise be exceeded.
+func planChunks(size, chunkSize int64, maxChunks int) []byteRange {
+	count := size / chunkSize
+	if size%chunkSize != 0 {
+		count++
+	}
+	if count < 1 {
+		count = 1
+	}
+	if count > int64(maxChunks) {
+		count = int64(maxChunks)
+		chunkSize = size / count
+		if size%count != 0 {
+			chunkSize++
+		}
+	}
+
+	chunks := make([]byteRange, 0, count)
+	for start := int64(0); start < size; start += chunkSize {
+		end := start + chunkSize - 1
+		if end >= size {
+			end = size - 1
+		}
+		chunks = append(chunks, byteRange{start: start, end: end})
+	}
+	return chunks
+}
+
+// probe checks whether url advertises Range support and returns its size.
+func (f *Fetcher) probe(ctx context.Context, url string) (size int64, rangeOK bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, false, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.ContentLength, resp.Header.Get("Accept-Ranges") == "bytes", nil
+}
+
+// Fetch downloads url into destPath, verifying the total size once every
+// chunk has landed. item labels progress events published for this fetch
+// (e.g. the destination filename).
+func (f *Fetcher) Fetch(ctx context.Context, url, destPath, item string) error {
+	size, rangeOK, err := f.probe(ctx, url)
+	if err != nil {
+		return fmt.Errorf("probing %s: %w", item, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return err
+	}
+
+	if !rangeOK || size <= 0 {
+		return f.fetchWhole(ctx, url, destPath, item)
+	}
+
+	tmpPath := destPath + ".part"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	if err := out.Truncate(size); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	chunks := planChunks(size, f.chunkSize, f.maxChunks)
+
+	var bar *console.Bar
+	if f.console != nil {
+		bar = f.console.AddBar(item, size)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, f.maxChunks)
+		mu        sync.Mutex
+		firstErr  error
+		completed int64
+		startTime = time.Now()
+	)
+
+	for _, c := range chunks {
+		c := c
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := f.fetchChunk(ctx, url, out, c, bar); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+
+			done := atomic.AddInt64(&completed, c.end-c.start+1)
+			if f.progress != nil {
+				var etaSeconds float64
+				if elapsed := time.Since(startTime); done > 0 {
+					etaSeconds = (elapsed.Seconds() / float64(done)) * float64(size-done)
+				}
+				f.progress.Publish(progress.Event{
+					Phase:      "fetch",
+					Completed:  int(done),
+					Total:      int(size),
+					Item:       item,
+					ETASeconds: etaSeconds,
+					Timestamp:  time.Now(),
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+	if bar != nil {
+		bar.Done()
+	}
+	closeErr := out.Close()
+
+	if firstErr != nil {
+		os.Remove(tmpPath)
+		return firstErr
+	}
+	if closeErr != nil {
+		os.Remove(tmpPath)
+		return closeErr
+	}
+
+	info, err := os.Stat(tmpPath)
+	if err != nil {
+		return err
+	}
+	if info.Size() != size {
+		os.Remove(tmpPath)
+		return fmt.Errorf("size mismatch for %s: expected %d bytes, got %d", item, size, info.Size())
+	}
+
+	return os.Rename(tmpPath, destPath)
+}
+
+// fetchChunk downloads one byte range and writes it at the matching offset.
+// bar may be nil; when set, every byte written also advances it.
+func (f *Fetcher) fetchChunk(ctx context.Context, url string, out *os.File, c byteRange, bar *console.Bar) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", c.start, c.end))
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching range %d-%d: %w", c.start, c.end, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d for range %d-%d", resp.StatusCode, c.start, c.end)
+	}
+
+	var body io.Reader = resp.Body
+	if bar != nil {
+		body = &barReader{r: resp.Body, bar: bar}
+	}
+
+	want := c.end - c.start + 1
+	n, err := io.Copy(io.NewOffsetWriter(out, c.start), body)
+	if err != nil {
+		return fmt.Errorf("writing range %d-%d: %w", c.start, c.end, err)
+	}
+	if n != want {
+		return fmt.Errorf("short read for range %d-%d: got %d of %d bytes", c.start, c.end, n, want)
+	}
+	return nil
+}
+
+// barReader advances bar as bytes are read, without marking it done on EOF —
+// chunks share one bar for the whole fetch, so only the fetch itself, once
+// every chunk has landed, gets to call bar.Done.
+type barReader struct {
+	r   io.Reader
+	bar *console.Bar
+}
+
+func (b *barReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if n > 0 {
+		b.bar.Add(int64(n))
+	}
+	return n, err
+}
+
+// fetchWhole streams the whole file in a single GET, for hosts that don't
+// support Range requests.
+func (f *Fetcher) fetchWhole(ctx context.Context, url, destPath, item string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	tmpPath := destPath + ".part"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader = resp.Body
+	if f.console != nil {
+		body = console.NewProgressReader(resp.Body, resp.ContentLength, item, f.console)
+	}
+
+	if _, err := io.Copy(out, body); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, destPath)
+}
@@ -0,0 +1,185 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// registry.go implements just enough of the Prometheus text exposition
+// format for robofuse's own metrics, rather than pulling in the full
+// client_golang dependency for a handful of counters/gauges/histograms.
+
+var syncDurationBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600}
+var waitBuckets = []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30}
+
+// Registry holds the counters/gauges/histograms robofuse reports on /metrics.
+type Registry struct {
+	linksUnrestrictedTotal int64
+	linksFailedTotal       int64
+	retryQueueDepth        int64
+	strmFiles              int64
+
+	syncDuration  *histogram
+	rateLimitWait *labeledHistogram
+	apiLatency    *labeledHistogram
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		syncDuration:  newHistogram(syncDurationBuckets),
+		rateLimitWait: newLabeledHistogram(waitBuckets),
+		apiLatency:    newLabeledHistogram(waitBuckets),
+	}
+}
+
+// AddLinksUnrestricted increments robofuse_links_unrestricted_total.
+func (r *Registry) AddLinksUnrestricted(n int) {
+	atomic.AddInt64(&r.linksUnrestrictedTotal, int64(n))
+}
+
+// AddLinksFailed increments robofuse_links_failed_total.
+func (r *Registry) AddLinksFailed(n int) {
+	atomic.AddInt64(&r.linksFailedTotal, int64(n))
+}
+
+// SetRetryQueueDepth sets robofuse_retry_queue_depth.
+func (r *Registry) SetRetryQueueDepth(n int) {
+	atomic.StoreInt64(&r.retryQueueDepth, int64(n))
+}
+
+// SetSTRMFiles sets robofuse_strm_files.
+func (r *Registry) SetSTRMFiles(n int) {
+	atomic.StoreInt64(&r.strmFiles, int64(n))
+}
+
+// ObserveSyncDuration records one robofuse_sync_duration_seconds observation.
+func (r *Registry) ObserveSyncDuration(seconds float64) {
+	r.syncDuration.observe(seconds)
+}
+
+// ObserveRateLimitWait records one robofuse_rate_limit_wait_seconds
+// observation for the given endpoint ("general", "torrents", "downloads").
+// Matches the func(endpoint string, seconds float64) signature
+// request.WithRateLimitObserver expects.
+func (r *Registry) ObserveRateLimitWait(endpoint string, seconds float64) {
+	r.rateLimitWait.observe(endpoint, seconds)
+}
+
+// ObserveAPILatency records one robofuse_api_request_duration_seconds
+// observation for the given endpoint. Matches the
+// func(endpoint string, seconds float64) signature request.WithLatencyObserver expects.
+func (r *Registry) ObserveAPILatency(endpoint string, seconds float64) {
+	r.apiLatency.observe(endpoint, seconds)
+}
+
+// Render writes every metric in Prometheus text exposition format.
+func (r *Registry) Render(w io.Writer) {
+	fmt.Fprintf(w, "# TYPE robofuse_links_unrestricted_total counter\n")
+	fmt.Fprintf(w, "robofuse_links_unrestricted_total %d\n", atomic.LoadInt64(&r.linksUnrestrictedTotal))
+
+	fmt.Fprintf(w, "# TYPE robofuse_links_failed_total counter\n")
+	fmt.Fprintf(w, "robofuse_links_failed_total %d\n", atomic.LoadInt64(&r.linksFailedTotal))
+
+	fmt.Fprintf(w, "# TYPE robofuse_retry_queue_depth gauge\n")
+	fmt.Fprintf(w, "robofuse_retry_queue_depth %d\n", atomic.LoadInt64(&r.retryQueueDepth))
+
+	fmt.Fprintf(w, "# TYPE robofuse_strm_files gauge\n")
+	fmt.Fprintf(w, "robofuse_strm_files %d\n", atomic.LoadInt64(&r.strmFiles))
+
+	r.syncDuration.writeTo(w, "robofuse_sync_duration_seconds")
+	r.rateLimitWait.writeTo(w, "robofuse_rate_limit_wait_seconds")
+	r.apiLatency.writeTo(w, "robofuse_api_request_duration_seconds")
+}
+
+// histogram is a fixed-bucket, cumulative (Prometheus-style) histogram.
+type histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sum += v
+	h.count++
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+}
+
+func (h *histogram) writeTo(w io.Writer, name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for i, b := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, b, h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %g\n", name, h.sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// labeledHistogram is a histogram keyed by a single "endpoint" label,
+// created lazily the first time each label is observed.
+type labeledHistogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	byLabel map[string]*histogram
+}
+
+func newLabeledHistogram(buckets []float64) *labeledHistogram {
+	return &labeledHistogram{buckets: buckets, byLabel: make(map[string]*histogram)}
+}
+
+func (lh *labeledHistogram) observe(label string, v float64) {
+	lh.mu.Lock()
+	h, ok := lh.byLabel[label]
+	if !ok {
+		h = newHistogram(lh.buckets)
+		lh.byLabel[label] = h
+	}
+	lh.mu.Unlock()
+
+	h.observe(v)
+}
+
+func (lh *labeledHistogram) writeTo(w io.Writer, name string) {
+	lh.mu.Lock()
+	labels := make([]string, 0, len(lh.byLabel))
+	for l := range lh.byLabel {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	lh.mu.Unlock()
+
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, label := range labels {
+		lh.mu.Lock()
+		h := lh.byLabel[label]
+		lh.mu.Unlock()
+
+		h.mu.Lock()
+		for i, b := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket{endpoint=%q,le=\"%g\"} %d\n", name, label, b, h.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{endpoint=%q,le=\"+Inf\"} %d\n", name, label, h.count)
+		fmt.Fprintf(w, "%s_sum{endpoint=%q} %g\n", name, label, h.sum)
+		fmt.Fprintf(w, "%s_count{endpoint=%q} %d\n", name, label, h.count)
+		h.mu.Unlock()
+	}
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/pkg/retry"
+	"github.com/robofuse/robofuse/pkg/tracking"
+	"github.com/rs/zerolog"
+)
+
+// server.go serves /metrics and an admin-token-gated /debug/queue dump of
+// the retry queue and tracking map.
+
+// DebugProvider supplies the data /debug/queue dumps.
+type DebugProvider interface {
+	RetryQueueItems() []*retry.RetryItem
+	TrackingSnapshot() map[string]*tracking.FileTracking
+}
+
+// Server exposes Registry over HTTP.
+type Server struct {
+	addr       string
+	adminToken string
+	registry   *Registry
+	debug      DebugProvider
+	logger     zerolog.Logger
+}
+
+// NewServer creates a Server that will listen on addr once started.
+// adminToken gates /debug/queue; an empty token disables that endpoint.
+func NewServer(addr, adminToken string, registry *Registry, debug DebugProvider) *Server {
+	return &Server{
+		addr:       addr,
+		adminToken: adminToken,
+		registry:   registry,
+		debug:      debug,
+		logger:     logger.New("metrics"),
+	}
+}
+
+// ListenAndServe serves until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/debug/queue", s.handleDebugQueue)
+
+	srv := &http.Server{Addr: s.addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	s.logger.Info().Str("addr", s.addr).Msg("Metrics server listening")
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.registry.Render(w)
+}
+
+func (s *Server) handleDebugQueue(w http.ResponseWriter, r *http.Request) {
+	if s.adminToken == "" || r.Header.Get("admin_token") != s.adminToken {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	resp := struct {
+		RetryQueue []*retry.RetryItem                `json:"retry_queue"`
+		Tracking   map[string]*tracking.FileTracking `json:"tracking"`
+	}{
+		RetryQueue: s.debug.RetryQueueItems(),
+		Tracking:   s.debug.TrackingSnapshot(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,56 @@
+package mount
+
+import (
+	"sync"
+	"time"
+)
+
+// cache.go holds the last resolved URL/size per tracked path, so Attr and
+// successive reads on the same Handle don't each re-unrestrict the link -
+// only a fresh Open, or a Read that turns up the link has expired, does.
+
+// linkCacheTTL bounds how long a resolved URL is reused before the next
+// Open re-resolves it, since debrid direct-download links themselves
+// expire independently of anything this package controls.
+const linkCacheTTL = 5 * time.Minute
+
+type cachedLink struct {
+	url        string
+	size       int64
+	resolvedAt time.Time
+}
+
+type linkCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedLink
+}
+
+func newLinkCache() *linkCache {
+	return &linkCache{entries: make(map[string]*cachedLink)}
+}
+
+func (c *linkCache) get(relPath string) (*cachedLink, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[relPath]
+	if !ok || time.Since(e.resolvedAt) > linkCacheTTL {
+		return nil, false
+	}
+	return e, true
+}
+
+func (c *linkCache) set(relPath, url string, size int64) *cachedLink {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &cachedLink{url: url, size: size, resolvedAt: time.Now()}
+	c.entries[relPath] = e
+	return e
+}
+
+func (c *linkCache) invalidate(relPath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, relPath)
+}
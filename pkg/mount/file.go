@@ -0,0 +1,96 @@
+package mount
+
+import (
+	"context"
+	"fmt"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/robofuse/robofuse/pkg/tracking"
+)
+
+// file.go resolves one tracked file's link on Open (and transparently
+// again on a Read that finds it's gone stale), instead of reading a
+// pre-written .strm pointer.
+
+// fileNode is one tracked file.
+type fileNode struct {
+	service *Service
+	relPath string
+	entry   *tracking.FileTracking
+}
+
+func (f *fileNode) Attr(ctx context.Context, attr *fuse.Attr) error {
+	attr.Mode = 0444
+	attr.Inode = inodeFor(f.relPath)
+
+	link, err := f.resolve(ctx)
+	if err != nil {
+		// Size stays 0 until a link resolves; the entry still shows up so
+		// a later Open can try again.
+		f.service.logger.Debug().Err(err).Str("path", f.relPath).Msg("Failed to resolve file size")
+		return nil
+	}
+	attr.Size = uint64(link.size)
+	return nil
+}
+
+func (f *fileNode) Open(ctx context.Context, req *fuse.OpenRequest, resp *fuse.OpenResponse) (fs.Handle, error) {
+	link, err := f.resolve(ctx)
+	if err != nil {
+		f.service.logger.Warn().Err(err).Str("path", f.relPath).Msg("Failed to resolve link on open")
+		return nil, fuse.EIO
+	}
+	return &fileHandle{service: f.service, relPath: f.relPath, entry: f.entry, link: link}, nil
+}
+
+// resolve returns the cached link for f, re-unrestricting it through
+// Service.resolver if it's missing or has outlived linkCacheTTL.
+func (f *fileNode) resolve(ctx context.Context) (*cachedLink, error) {
+	if link, ok := f.service.cache.get(f.relPath); ok {
+		return link, nil
+	}
+
+	downloadURL, err := f.service.resolver.ResolveLink(ctx, f.entry.Provider, f.entry.Link)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", f.relPath, err)
+	}
+
+	size, err := probeSize(ctx, downloadURL)
+	if err != nil {
+		f.service.logger.Debug().Err(err).Str("path", f.relPath).Msg("Failed to probe file size, reporting 0")
+	}
+
+	return f.service.cache.set(f.relPath, downloadURL, size), nil
+}
+
+// fileHandle reads from whichever URL was current as of Open, re-resolving
+// once through the provider if a read comes back stale.
+type fileHandle struct {
+	service *Service
+	relPath string
+	entry   *tracking.FileTracking
+	link    *cachedLink
+}
+
+func (h *fileHandle) Read(ctx context.Context, req *fuse.ReadRequest, resp *fuse.ReadResponse) error {
+	data, err := rangedRead(ctx, h.link.url, req.Offset, req.Size)
+	if err != nil {
+		h.service.logger.Debug().Err(err).Str("path", h.relPath).Msg("Read failed, re-resolving link")
+		h.service.cache.invalidate(h.relPath)
+
+		downloadURL, rerr := h.service.resolver.ResolveLink(ctx, h.entry.Provider, h.entry.Link)
+		if rerr != nil {
+			return fuse.EIO
+		}
+		h.link = h.service.cache.set(h.relPath, downloadURL, h.link.size)
+
+		data, err = rangedRead(ctx, h.link.url, req.Offset, req.Size)
+		if err != nil {
+			return fuse.EIO
+		}
+	}
+
+	resp.Data = data
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package mount exposes the tracked STRM candidate set as a read-only FUSE
+// filesystem: each virtual file resolves through the configured debrid
+// provider on Open, and transparently again if the cached link has gone
+// stale by the time it's read, instead of needing a materialized .strm
+// pointer file on disk. Sync still runs periodically (as with strm.Service)
+// to keep the tracked set itself current; this package only changes how
+// the files it describes are served.
+package mount
+
+import (
+	"context"
+	"fmt"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/pkg/tracking"
+	"github.com/rs/zerolog"
+)
+
+// Resolver re-unrestricts a tracked link into a fresh direct-download URL,
+// routed to whichever provider account originally produced it. sync.Service
+// satisfies this via its existing per-account routing.
+type Resolver interface {
+	ResolveLink(ctx context.Context, provider, link string) (downloadURL string, err error)
+}
+
+// Service serves tracking's tracked files as a FUSE filesystem.
+type Service struct {
+	tracking *tracking.Service
+	resolver Resolver
+	logger   zerolog.Logger
+
+	cache *linkCache
+}
+
+// New creates a mount Service. trackingSvc supplies the virtual file tree;
+// resolver turns a tracked link back into a playable URL on demand.
+func New(trackingSvc *tracking.Service, resolver Resolver) *Service {
+	return &Service{
+		tracking: trackingSvc,
+		resolver: resolver,
+		logger:   logger.New("mount"),
+		cache:    newLinkCache(),
+	}
+}
+
+// Mount serves the filesystem at mountpoint until ctx is cancelled.
+func (s *Service) Mount(ctx context.Context, mountpoint string) error {
+	conn, err := fuse.Mount(
+		mountpoint,
+		fuse.ReadOnly(),
+		fuse.FSName("robofuse"),
+		fuse.Subtype("robofuse"),
+	)
+	if err != nil {
+		return fmt.Errorf("mount: %w", err)
+	}
+	defer conn.Close()
+
+	s.logger.Info().Str("mountpoint", mountpoint).Msg("Serving virtual library")
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- fs.Serve(conn, s) }()
+
+	select {
+	case <-ctx.Done():
+		if err := fuse.Unmount(mountpoint); err != nil {
+			s.logger.Warn().Err(err).Msg("Failed to unmount cleanly")
+		}
+		<-errCh
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
+
+// Root implements fs.FS.
+func (s *Service) Root() (fs.Node, error) {
+	return &dirNode{service: s, prefix: ""}, nil
+}
@@ -0,0 +1,98 @@
+package mount
+
+import (
+	"context"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"bazil.org/fuse"
+	"bazil.org/fuse/fs"
+)
+
+// tree.go builds the directory tree fs.Serve walks on demand from the
+// tracked file set, rather than keeping a persistent in-memory copy - the
+// tracked set can change out from under a long-lived mount whenever Sync
+// runs, so each Lookup/ReadDirAll call re-derives its answer from the
+// current tracking.Service.All() snapshot.
+
+// dirNode is a directory at prefix (relative to the mount root, "" for the
+// root itself), derived from every tracked path that starts with it.
+type dirNode struct {
+	service *Service
+	prefix  string
+}
+
+func (d *dirNode) Attr(ctx context.Context, attr *fuse.Attr) error {
+	attr.Mode = os.ModeDir | 0555
+	attr.Inode = inodeFor(d.prefix)
+	return nil
+}
+
+// children returns the immediate subdirectory and file names directly
+// under prefix.
+func (d *dirNode) children() (dirs map[string]bool, files map[string]string) {
+	dirs = make(map[string]bool)
+	files = make(map[string]string)
+
+	for relPath := range d.service.tracking.All() {
+		if d.prefix != "" {
+			if !strings.HasPrefix(relPath, d.prefix+string(filepath.Separator)) {
+				continue
+			}
+			relPath = strings.TrimPrefix(relPath, d.prefix+string(filepath.Separator))
+		}
+
+		parts := strings.SplitN(relPath, string(filepath.Separator), 2)
+		if len(parts) == 1 {
+			files[parts[0]] = relPath
+		} else {
+			dirs[parts[0]] = true
+		}
+	}
+
+	return dirs, files
+}
+
+func (d *dirNode) Lookup(ctx context.Context, name string) (fs.Node, error) {
+	dirs, files := d.children()
+
+	if _, ok := files[name]; ok {
+		relPath := filepath.Join(d.prefix, name)
+		entry, ok := d.service.tracking.Get(relPath)
+		if !ok {
+			return nil, fuse.ENOENT
+		}
+		return &fileNode{service: d.service, relPath: relPath, entry: entry}, nil
+	}
+
+	if dirs[name] {
+		return &dirNode{service: d.service, prefix: filepath.Join(d.prefix, name)}, nil
+	}
+
+	return nil, fuse.ENOENT
+}
+
+func (d *dirNode) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
+	dirs, files := d.children()
+
+	ents := make([]fuse.Dirent, 0, len(dirs)+len(files))
+	for name := range dirs {
+		ents = append(ents, fuse.Dirent{Inode: inodeFor(filepath.Join(d.prefix, name)), Type: fuse.DT_Dir, Name: name})
+	}
+	for name, relPath := range files {
+		ents = append(ents, fuse.Dirent{Inode: inodeFor(relPath), Type: fuse.DT_File, Name: name})
+	}
+
+	return ents, nil
+}
+
+// inodeFor derives a stable-enough inode number from a relative path; the
+// kernel only needs these to be distinct per entry, not persistent across
+// remounts.
+func inodeFor(relPath string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(relPath))
+	return h.Sum64()
+}
@@ -0,0 +1,173 @@
+package organizer
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cespare/xxhash/v2"
+	"go.etcd.io/bbolt"
+
+	"github.com/robofuse/robofuse/internal/store"
+)
+
+// hash.go computes and tracks content hashes of organized files, modeled on
+// how rclone's mailru backend keeps a per-file hash alongside its metadata
+// for integrity checking. Hashing is opt-in (Config.HashAlgorithm) since it
+// means reading every source file in full on every Run.
+
+// newHasher returns a hash.Hash for algo ("sha1" or "xxhash").
+func newHasher(algo string) (hash.Hash, error) {
+	switch algo {
+	case "sha1":
+		return sha1.New(), nil
+	case "xxhash":
+		return xxhash.New(), nil
+	default:
+		return nil, fmt.Errorf("organizer: unknown hash algorithm %q", algo)
+	}
+}
+
+// hashFile computes path's content hash under algo without writing it
+// anywhere, for Verify's re-check pass.
+func hashFile(path, algo string) (string, error) {
+	h, err := newHasher(algo)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// findDuplicateDest scans organized for an entry, other than excludeRelPath,
+// whose recorded hash matches hash and whose destination file still exists,
+// so the caller can link to it instead of copying the same bytes again.
+func (o *Organizer) findDuplicateDest(organized *bbolt.Bucket, hashVal, algo, excludeRelPath string) (string, bool) {
+	if hashVal == "" {
+		return "", false
+	}
+
+	var destRelPath string
+	_ = organized.ForEach(func(k, v []byte) error {
+		if destRelPath != "" || string(k) == excludeRelPath {
+			return nil
+		}
+		var entry FileEntry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return nil
+		}
+		if entry.Hash != hashVal || entry.HashAlgorithm != algo {
+			return nil
+		}
+		if !fileExists(filepath.Join(o.organizedDir, entry.DestPath)) {
+			return nil
+		}
+		destRelPath = entry.DestPath
+		return nil
+	})
+
+	return destRelPath, destRelPath != ""
+}
+
+// findDuplicateDestInSnapshot is findDuplicateDest's counterpart for
+// RunContext's worker pool, which searches a pre-run snapshot of the
+// organized bucket instead of the live bucket so concurrent workers can
+// look up duplicates without touching the shared *bbolt.Tx.
+func (o *Organizer) findDuplicateDestInSnapshot(snapshot map[string]FileEntry, hashVal, algo, excludeRelPath string) (string, bool) {
+	if hashVal == "" {
+		return "", false
+	}
+
+	for relPath, entry := range snapshot {
+		if relPath == excludeRelPath {
+			continue
+		}
+		if entry.Hash != hashVal || entry.HashAlgorithm != algo {
+			continue
+		}
+		if !fileExists(filepath.Join(o.organizedDir, entry.DestPath)) {
+			continue
+		}
+		return entry.DestPath, true
+	}
+
+	return "", false
+}
+
+// linkDuplicate hard-links destFullPath to an already-organized file with
+// the same content hash, falling back to a symlink across filesystems, so
+// two tracked entries for the same bytes (e.g. the same episode behind two
+// Real-Debrid IDs) share one copy on disk.
+func linkDuplicate(existingFullPath, destFullPath string) error {
+	if err := os.MkdirAll(filepath.Dir(destFullPath), 0755); err != nil {
+		return err
+	}
+	if err := os.Link(existingFullPath, destFullPath); err == nil {
+		return nil
+	}
+	return os.Symlink(existingFullPath, destFullPath)
+}
+
+// VerifyResult summarizes a Verify pass over the organized tree.
+type VerifyResult struct {
+	Checked    int      `json:"checked"`
+	Mismatches []string `json:"mismatches,omitempty"`
+	Missing    []string `json:"missing,omitempty"`
+}
+
+// Verify walks the organized tree and recomputes each hashed entry's
+// content hash, reporting any that no longer match what's recorded in the
+// organized bucket (bit rot, a manual edit, a bad copy) or that are
+// missing from disk entirely. Entries organized without hashing enabled
+// are skipped, since there's nothing recorded to check them against.
+func (o *Organizer) Verify() (VerifyResult, error) {
+	var result VerifyResult
+
+	err := o.store.View(func(tx *bbolt.Tx) error {
+		organized := store.Bucket(tx, store.OrganizedBucket)
+
+		return organized.ForEach(func(_, v []byte) error {
+			var entry FileEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return nil
+			}
+			if entry.HashAlgorithm == "" || entry.Hash == "" {
+				return nil
+			}
+
+			destFullPath := filepath.Join(o.organizedDir, entry.DestPath)
+			if !fileExists(destFullPath) {
+				result.Missing = append(result.Missing, entry.DestPath)
+				return nil
+			}
+
+			result.Checked++
+			actual, err := hashFile(destFullPath, entry.HashAlgorithm)
+			if err != nil {
+				o.logger.Warn().Err(err).Str("path", entry.DestPath).Msg("Failed to hash organized file during verify")
+				return nil
+			}
+			if actual != entry.Hash {
+				result.Mismatches = append(result.Mismatches, entry.DestPath)
+			}
+			return nil
+		})
+	})
+
+	return result, err
+}
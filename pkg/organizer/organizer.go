@@ -4,16 +4,21 @@
 package organizer
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
+	"sync"
 
 	ptt "github.com/itsrenoria/ptt-go"
+	"github.com/robofuse/robofuse/internal/diskspace"
+	"github.com/robofuse/robofuse/internal/store"
 	"github.com/rs/zerolog"
+	"go.etcd.io/bbolt"
 )
 
 // Result contains statistics from the organization process.
@@ -28,11 +33,21 @@ type Result struct {
 
 // FileEntry represents a tracked file in the organizer database.
 type FileEntry struct {
-	DestPath    string `json:"dest_path"`
-	RDID        string `json:"rd_id"`
-	Type        string `json:"type"`
+	DestPath string `json:"dest_path"`
+	RDID     string `json:"rd_id"`
+	Type     string `json:"type"`
+	Category string `json:"category,omitempty"`
+	// Provider is the debrid backend (e.g. "real-debrid", "alldebrid")
+	// that produced this file, so an account swap is detected as a change
+	// rather than silently reusing another provider's cached copy.
+	Provider    string `json:"provider,omitempty"`
 	DownloadURL string `json:"download_url,omitempty"`
 	UpdatedAt   string `json:"updated_at,omitempty"`
+
+	// Hash is the content hash of the source file as of the last copy,
+	// computed with HashAlgorithm. Both are empty when hashing is disabled.
+	Hash          string `json:"hash,omitempty"`
+	HashAlgorithm string `json:"hash_algorithm,omitempty"`
 }
 
 // TrackingEntry represents an entry from the file tracking system.
@@ -40,20 +55,43 @@ type TrackingEntry struct {
 	Link        string `json:"link"`
 	DownloadURL string `json:"download_url,omitempty"`
 	LastChecked string `json:"last_checked,omitempty"`
+	TorrentID   string `json:"torrent_id,omitempty"`
+	Provider    string `json:"provider,omitempty"`
 }
 
 // Organizer handles media file organization.
 type Organizer struct {
-	baseDir      string
-	libraryDir   string
-	organizedDir string
-	dbPath       string
-	trackingPath string
-	parser       *ptt.Parser
-	logger       zerolog.Logger
-	db           map[string]FileEntry
+	baseDir        string
+	libraryDir     string
+	organizedDir   string
+	legacyDBPath   string
+	legacyTracking string
+	parser         *ptt.Parser
+	logger         zerolog.Logger
+	store          *store.DB
+	ownsStore      bool
+	categoryOf     CategoryLookup
+	diskGuard      *diskspace.Guard
+	hashAlgorithm  string
+	placer         Placer
+	workers        int
+
+	// folderResolveMu serializes getContentTypeAndPath (and the
+	// findExistingSeriesFolder lookup inside it) across RunContext's
+	// worker pool, so two episodes of the same series parsed with
+	// slightly different capitalization can't each decide the series
+	// needs a new folder of its own.
+	folderResolveMu sync.Mutex
+	// destDirLocks serializes placement into a given destination
+	// directory, keyed by filepath.Dir(destRelPath), so two workers can't
+	// race to create the same season folder.
+	destDirLocks *dirLocker
 }
 
+// CategoryLookup resolves the caller-supplied category (e.g. from the
+// qBittorrent shim) for a Real-Debrid torrent ID, or "" if none is known.
+type CategoryLookup func(torrentID string) string
+
 // Config holds organizer configuration.
 type Config struct {
 	BaseDir      string
@@ -62,6 +100,45 @@ type Config struct {
 	TrackingFile string
 	CacheDir     string
 	Logger       zerolog.Logger
+
+	// Store is the shared bbolt store callers (e.g. pkg/sync) already hold
+	// open; the organizer reads the "tracking" bucket from it and keeps
+	// its own state in the "organized" bucket. If nil, New opens its own
+	// store.DB at CacheDir/robofuse.db.
+	Store *store.DB
+
+	// CategoryLookup, if set, routes organized files for a torrent into a
+	// per-category subfolder of OrganizedDir instead of the bare
+	// Movies/Series/Anime layout.
+	CategoryLookup CategoryLookup
+
+	// DiskGuard, if set, is consulted before every per-file copy so the
+	// organizer pauses rather than running OrganizedDir's volume out of
+	// space mid-run. Callers typically share the same Guard used to gate
+	// sync's own fetch/unrestrict steps. A nil DiskGuard disables the check.
+	DiskGuard *diskspace.Guard
+
+	// HashAlgorithm enables content-hash tracking of organized files:
+	// "sha1" or "xxhash". Empty disables hashing, which is the default
+	// since it requires reading every source file in full on each Run.
+	// When set, a source file whose hash matches its last recorded hash
+	// is skipped even if its DownloadURL changed, and two tracked files
+	// that hash identically (e.g. the same episode behind two
+	// Real-Debrid IDs) are hard-linked together instead of copied twice.
+	HashAlgorithm string
+
+	// PlacementMode selects how an organized file is physically placed
+	// relative to its source: PlacementCopy (the default) does a full
+	// byte copy; PlacementHardlink, PlacementSymlink, and PlacementReflink
+	// avoid doubling disk usage when the source and organized tree share
+	// a filesystem, e.g. an rclone-mounted Real-Debrid library. Hardlink
+	// and Reflink fall back to a copy when the strategy isn't supported
+	// (most commonly src/dst on different filesystems).
+	PlacementMode PlacementMode
+
+	// Workers bounds RunContext's worker pool, which processes tracked
+	// files concurrently. Defaults to runtime.NumCPU() when unset.
+	Workers int
 }
 
 // New creates a new Organizer instance.
@@ -91,58 +168,75 @@ func New(cfg Config) *Organizer {
 		trackingPath = filepath.Join(cfg.BaseDir, "cache", "file_tracking.json")
 	}
 
-	return &Organizer{
-		baseDir:      cfg.BaseDir,
-		libraryDir:   libraryDir,
-		organizedDir: organizedDir,
-		dbPath:       filepath.Join(cacheDir, "organizer_db.json"),
-		trackingPath: trackingPath,
-		parser:       parser,
-		logger:       cfg.Logger,
-		db:           make(map[string]FileEntry),
+	db := cfg.Store
+	ownsStore := false
+	if db == nil {
+		var err error
+		db, err = store.Open(filepath.Join(cacheDir, "robofuse.db"))
+		if err != nil {
+			cfg.Logger.Error().Err(err).Msg("Failed to open organizer store")
+		}
+		ownsStore = true
 	}
-}
 
-// loadDB loads the organizer database from disk.
-func (o *Organizer) loadDB() error {
-	data, err := os.ReadFile(o.dbPath)
-	if os.IsNotExist(err) {
-		o.db = make(map[string]FileEntry)
-		return nil
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
 	}
-	if err != nil {
-		return err
+
+	return &Organizer{
+		baseDir:        cfg.BaseDir,
+		libraryDir:     libraryDir,
+		organizedDir:   organizedDir,
+		legacyDBPath:   filepath.Join(cacheDir, "organizer_db.json"),
+		legacyTracking: trackingPath,
+		parser:         parser,
+		logger:         cfg.Logger,
+		store:          db,
+		ownsStore:      ownsStore,
+		categoryOf:     cfg.CategoryLookup,
+		diskGuard:      cfg.DiskGuard,
+		hashAlgorithm:  strings.ToLower(cfg.HashAlgorithm),
+		placer:         newPlacer(cfg.PlacementMode),
+		workers:        workers,
+		destDirLocks:   newDirLocker(),
 	}
-	return json.Unmarshal(data, &o.db)
 }
 
-// saveDB saves the organizer database to disk.
-func (o *Organizer) saveDB() error {
-	if err := os.MkdirAll(filepath.Dir(o.dbPath), 0755); err != nil {
-		return err
-	}
-	data, err := json.MarshalIndent(o.db, "", "  ")
-	if err != nil {
-		return err
+// Close releases the organizer's store if New opened it itself (i.e. no
+// Store was supplied in Config). It's a no-op when the store is shared.
+func (o *Organizer) Close() error {
+	if o.ownsStore && o.store != nil {
+		return o.store.Close()
 	}
-	return os.WriteFile(o.dbPath, data, 0644)
+	return nil
 }
 
-// loadTracking loads the file tracking database.
-func (o *Organizer) loadTracking() (map[string]TrackingEntry, error) {
-	data, err := os.ReadFile(o.trackingPath)
-	if os.IsNotExist(err) {
-		return make(map[string]TrackingEntry), nil
-	}
-	if err != nil {
-		return nil, err
-	}
+// Get returns the organized-file entry for relPath, for callers (and
+// tests) that need to inspect where a file landed.
+func (o *Organizer) Get(relPath string) (FileEntry, bool) {
+	var entry FileEntry
+	found, _ := o.store.Get(store.OrganizedBucket, relPath, &entry)
+	return entry, found
+}
 
-	var tracking map[string]TrackingEntry
-	if err := json.Unmarshal(data, &tracking); err != nil {
-		return nil, err
-	}
-	return tracking, nil
+// PendingCount returns the number of tracked files that don't yet have a
+// corresponding organized entry, a cheap approximation of what the next
+// RunContext sweep's New count will be, for status reporting that can't
+// afford a full sweep just to answer "is there pending work".
+func (o *Organizer) PendingCount() (int, error) {
+	var pending int
+	err := o.store.View(func(tx *bbolt.Tx) error {
+		tracking := store.Bucket(tx, store.TrackingBucket)
+		organized := store.Bucket(tx, store.OrganizedBucket)
+		return tracking.ForEach(func(k, _ []byte) error {
+			if organized.Get(k) == nil {
+				pending++
+			}
+			return nil
+		})
+	})
+	return pending, err
 }
 
 var rdIDRegex = regexp.MustCompile(`/d/([a-zA-Z0-9]+)`)
@@ -159,6 +253,14 @@ func getRDIDFromLink(link string) string {
 	return ""
 }
 
+// lookupCategory resolves torrentID's category via categoryOf, if configured.
+func (o *Organizer) lookupCategory(torrentID string) string {
+	if o.categoryOf == nil || torrentID == "" {
+		return ""
+	}
+	return o.categoryOf(torrentID)
+}
+
 var illegalCharsRegex = regexp.MustCompile(`[<>:"/\\|?*]`)
 
 // cleanFilename removes illegal filesystem characters.
@@ -368,129 +470,186 @@ func (o *Organizer) getContentTypeAndPath(parsed, parentParsed *ptt.TorrentInfo,
 	return finalType, destPath
 }
 
-// copyFile copies a file from src to dst.
-func copyFile(src, dst string) error {
-	sourceFile, err := os.Open(src)
-	if err != nil {
-		return err
+// organizeEntry resolves and applies relPath's placement against the live
+// organized bucket, used by Watch's single-path reconciliation where
+// there's no worker pool and no snapshot to share.
+func (o *Organizer) organizeEntry(ctx context.Context, organized *bbolt.Bucket, relPath string, meta TrackingEntry, result *Result) error {
+	var prevEntry FileEntry
+	hadPrev := false
+	if raw := organized.Get([]byte(relPath)); raw != nil {
+		if err := json.Unmarshal(raw, &prevEntry); err == nil {
+			hadPrev = true
+		}
 	}
-	defer sourceFile.Close()
 
-	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
-		return err
+	entry, skip, err := o.resolveEntry(ctx, relPath, meta, prevEntry, hadPrev, func(hashVal, algo string) (string, bool) {
+		return o.findDuplicateDest(organized, hashVal, algo, relPath)
+	}, func(hashVal, algo, destRelPath string) {})
+	if err != nil {
+		result.Errors++
+		return nil
+	}
+	if skip {
+		result.Skipped++
+		return nil
 	}
 
-	destFile, err := os.Create(dst)
+	data, err := json.Marshal(entry)
 	if err != nil {
-		return err
+		o.logger.Error().Err(err).Str("path", relPath).Msg("Failed to encode organized entry")
+		result.Errors++
+		return nil
 	}
-	defer destFile.Close()
-
-	_, err = io.Copy(destFile, sourceFile)
-	return err
+	result.New++
+	return organized.Put([]byte(relPath), data)
 }
 
-// Run executes the organization process.
-func (o *Organizer) Run() Result {
-	result := Result{}
-
-	if err := o.loadDB(); err != nil {
-		o.logger.Error().Err(err).Msg("Failed to load organizer database")
-		return result
+// resolveEntry decides what relPath's organized entry should be - whether
+// it's already up to date and can be skipped, and if not, physically
+// places it and returns the FileEntry to persist. It touches no bucket
+// itself, so both organizeEntry's live-bucket lookups (Watch) and
+// RunContext's worker pool (sharing a read-only pre-run snapshot instead)
+// can use it without racing on a *bbolt.Tx. findDuplicate resolves a
+// content-hash match to a destination path the caller can link to, using
+// whichever view of the organized bucket the caller holds; recordPlaced
+// is called with the same lock held once placement finishes, so a
+// duplicate landing in the same destination directory right behind this
+// one (organizeEntry's live bucket already reflects it; RunContext's
+// worker pool has no live bucket to read mid-run, so it uses recordPlaced
+// to fill that gap) can still find it via findDuplicate.
+func (o *Organizer) resolveEntry(ctx context.Context, relPath string, meta TrackingEntry, prevEntry FileEntry, hadPrev bool, findDuplicate func(hashVal, algo string) (string, bool), recordPlaced func(hashVal, algo, destRelPath string)) (FileEntry, bool, error) {
+	sourceFullPath := filepath.Join(o.libraryDir, relPath)
+	rdID := getRDIDFromLink(meta.Link)
+	category := o.lookupCategory(meta.TorrentID)
+
+	// When hashing is enabled, hash the source up front: it's both the
+	// skip check's strongest signal (content unchanged even if the
+	// upstream DownloadURL churned) and the key used to spot duplicates.
+	var sourceHash string
+	if o.hashAlgorithm != "" {
+		h, err := hashFile(sourceFullPath, o.hashAlgorithm)
+		if err != nil {
+			o.logger.Warn().Err(err).Str("path", relPath).Msg("Failed to hash source file")
+		} else {
+			sourceHash = h
+		}
 	}
 
-	tracking, err := o.loadTracking()
-	if err != nil {
-		o.logger.Error().Err(err).Msg("Failed to load tracking database")
-		return result
+	// Check if already organized and up to date
+	if hadPrev {
+		destFullPath := filepath.Join(o.organizedDir, prevEntry.DestPath)
+		sameURL := meta.DownloadURL != "" && prevEntry.DownloadURL == meta.DownloadURL
+		sameHash := sourceHash != "" && prevEntry.HashAlgorithm == o.hashAlgorithm && prevEntry.Hash == sourceHash
+		if prevEntry.RDID == rdID && prevEntry.Category == category && prevEntry.Provider == meta.Provider && fileExists(destFullPath) && (sameURL || meta.DownloadURL == "" || sameHash) {
+			return FileEntry{}, true, nil
+		}
 	}
 
-	result.Processed = len(tracking)
-	currentSourcePaths := make(map[string]bool)
-	newState := make(map[string]FileEntry)
+	// Needs organization
 
-	for relPath, meta := range tracking {
-		sourceFullPath := filepath.Join(o.libraryDir, relPath)
-		if !fileExists(sourceFullPath) {
-			continue
-		}
-		currentSourcePaths[relPath] = true
-
-		// Check if already organized and up to date
-		if prevEntry, exists := o.db[relPath]; exists {
-			currentID := getRDIDFromLink(meta.Link)
-			destFullPath := filepath.Join(o.organizedDir, prevEntry.DestPath)
-			sameURL := meta.DownloadURL != "" && prevEntry.DownloadURL == meta.DownloadURL
-			if prevEntry.RDID == currentID && fileExists(destFullPath) && (sameURL || meta.DownloadURL == "") {
-				newState[relPath] = prevEntry
-				result.Skipped++
-				continue
-			}
-		}
+	// Parse filename
+	filename := filepath.Base(relPath)
+	nameNoExt := strings.TrimSuffix(filename, filepath.Ext(filename))
 
-		// Needs organization
+	// Parse parent folder
+	parentRelDir := filepath.Dir(relPath)
+	parentFolderName := ""
+	if parentRelDir != "" && parentRelDir != "." {
+		parentFolderName = filepath.Base(parentRelDir)
+	}
 
-		// Parse filename
-		filename := filepath.Base(relPath)
-		nameNoExt := strings.TrimSuffix(filename, filepath.Ext(filename))
-		parsed := o.parser.Parse(nameNoExt)
+	// getContentTypeAndPath reads the organized tree (findExistingSeriesFolder)
+	// to decide a series' folder, so it's serialized across the worker pool.
+	o.folderResolveMu.Lock()
+	parsed := o.parser.Parse(nameNoExt)
+	var parentParsed *ptt.TorrentInfo
+	if parentFolderName != "" {
+		parentParsed = o.parser.Parse(parentFolderName)
+	}
+	contentType, destRelPath := o.getContentTypeAndPath(parsed, parentParsed, filename, rdID)
+	o.folderResolveMu.Unlock()
 
-		// Parse parent folder
-		parentRelDir := filepath.Dir(relPath)
-		parentFolderName := ""
-		if parentRelDir != "" && parentRelDir != "." {
-			parentFolderName = filepath.Base(parentRelDir)
-		}
+	if category != "" {
+		destRelPath = filepath.Join(cleanFilename(category), destRelPath)
+	}
+	destFullPath := filepath.Join(o.organizedDir, destRelPath)
 
-		var parentParsed *ptt.TorrentInfo
-		if parentFolderName != "" {
-			parentParsed = o.parser.Parse(parentFolderName)
+	if o.diskGuard != nil {
+		if _, err := o.diskGuard.Allow(ctx); err != nil {
+			return FileEntry{}, false, err
 		}
+	}
 
-		rdID := getRDIDFromLink(meta.Link)
-
-		// Determine destination
-		contentType, destRelPath := o.getContentTypeAndPath(parsed, parentParsed, filename, rdID)
-		destFullPath := filepath.Join(o.organizedDir, destRelPath)
+	// Serialize the actual placement per destination directory, so two
+	// workers landing in the same season folder can't race to create it.
+	unlock := o.destDirLocks.Lock(filepath.Dir(destRelPath))
+	defer unlock()
+
+	// If another tracked file already organized to the same content hash
+	// (e.g. the same episode behind two Real-Debrid IDs), link to it
+	// instead of copying the bytes again.
+	copiedHash := sourceHash
+	linked := false
+	if sourceHash != "" {
+		if dupDest, ok := findDuplicate(sourceHash, o.hashAlgorithm); ok {
+			if err := linkDuplicate(filepath.Join(o.organizedDir, dupDest), destFullPath); err == nil {
+				linked = true
+			} else {
+				o.logger.Warn().Err(err).Str("path", relPath).Msg("Failed to link duplicate organized file, falling back to copy")
+			}
+		}
+	}
 
-		// Copy file
-		if err := copyFile(sourceFullPath, destFullPath); err != nil {
+	if !linked {
+		if err := o.placer.Place(ctx, sourceFullPath, destFullPath); err != nil {
 			o.logger.Error().Err(err).Str("path", relPath).Msg("Failed to organize file")
-			result.Errors++
-			continue
+			return FileEntry{}, false, err
 		}
+	}
 
-		newState[relPath] = FileEntry{
-			DestPath:    destRelPath,
-			RDID:        rdID,
-			Type:        contentType,
-			DownloadURL: meta.DownloadURL,
-			UpdatedAt:   meta.LastChecked,
-		}
-		result.New++
-	}
-
-	// Cleanup deleted files
-	for oldSrcPath, oldEntry := range o.db {
-		if !currentSourcePaths[oldSrcPath] {
-			destFull := filepath.Join(o.organizedDir, oldEntry.DestPath)
-			if fileExists(destFull) {
-				if err := os.Remove(destFull); err == nil {
-					result.Deleted++
-					// Try to remove empty parent directories
-					o.cleanEmptyDirs(filepath.Dir(destFull))
-				}
-			}
-		}
+	if copiedHash != "" {
+		recordPlaced(copiedHash, o.hashAlgorithm, destRelPath)
 	}
 
-	// Save new state
-	o.db = newState
-	if err := o.saveDB(); err != nil {
-		o.logger.Error().Err(err).Msg("Failed to save organizer database")
+	return FileEntry{
+		DestPath:      destRelPath,
+		RDID:          rdID,
+		Type:          contentType,
+		Category:      category,
+		Provider:      meta.Provider,
+		DownloadURL:   meta.DownloadURL,
+		UpdatedAt:     meta.LastChecked,
+		Hash:          copiedHash,
+		HashAlgorithm: o.hashAlgorithm,
+	}, false, nil
+}
+
+// removeOrganized deletes relPath's mirrored output file (if any) and its
+// organized-bucket entry. Used both by Run's sweep and Watch's per-path
+// removal handling.
+func (o *Organizer) removeOrganized(organized *bbolt.Bucket, relPath string) (deleted bool, err error) {
+	raw := organized.Get([]byte(relPath))
+	if raw == nil {
+		return false, nil
+	}
+
+	var entry FileEntry
+	if err := json.Unmarshal(raw, &entry); err == nil {
+		destFull := filepath.Join(o.organizedDir, entry.DestPath)
+		// Lstat, not fileExists: a symlink placement whose source has
+		// already disappeared fails a Stat (broken link) but still has a
+		// directory entry on the organized side that needs cleaning up.
+		// os.Remove only ever unlinks that entry, so it never touches the
+		// source even for hardlink/symlink placements.
+		if _, err := os.Lstat(destFull); err == nil {
+			if err := os.Remove(destFull); err == nil {
+				deleted = true
+				o.cleanEmptyDirs(filepath.Dir(destFull))
+			}
+		}
 	}
 
-	return result
+	return deleted, organized.Delete([]byte(relPath))
 }
 
 // cleanEmptyDirs removes empty directories up to the organized root.
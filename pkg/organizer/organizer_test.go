@@ -1,11 +1,13 @@
 package organizer
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/robofuse/robofuse/internal/store"
 	"github.com/rs/zerolog"
 )
 
@@ -34,38 +36,28 @@ func TestRun_RemovesOrganizedWhenSourceMissing(t *testing.T) {
 		t.Fatalf("write organized file: %v", err)
 	}
 
-	// Tracking entry exists but source file is missing.
-	trackingPath := filepath.Join(cacheDir, "file_tracking.json")
-	tracking := map[string]TrackingEntry{
-		relPath: {Link: "https://real-debrid.com/d/ABC123"},
-	}
-	trackingBytes, err := json.Marshal(tracking)
+	db, err := store.Open(filepath.Join(cacheDir, "robofuse.db"))
 	if err != nil {
-		t.Fatalf("marshal tracking: %v", err)
+		t.Fatalf("store.Open: %v", err)
 	}
-	if err := os.WriteFile(trackingPath, trackingBytes, 0644); err != nil {
-		t.Fatalf("write tracking: %v", err)
+	defer db.Close()
+
+	// Tracking entry exists but source file is missing.
+	if err := db.Put(store.TrackingBucket, relPath, &TrackingEntry{Link: "https://real-debrid.com/d/ABC123"}); err != nil {
+		t.Fatalf("seed tracking: %v", err)
 	}
 
 	// Organizer DB references the organized file.
-	dbPath := filepath.Join(cacheDir, "organizer_db.json")
-	db := map[string]FileEntry{
-		relPath: {DestPath: relPath, RDID: "ABC123", Type: "movie"},
-	}
-	dbBytes, err := json.Marshal(db)
-	if err != nil {
-		t.Fatalf("marshal db: %v", err)
-	}
-	if err := os.WriteFile(dbPath, dbBytes, 0644); err != nil {
-		t.Fatalf("write db: %v", err)
+	if err := db.Put(store.OrganizedBucket, relPath, &FileEntry{DestPath: relPath, RDID: "ABC123", Type: "movie"}); err != nil {
+		t.Fatalf("seed organized entry: %v", err)
 	}
 
 	org := New(Config{
 		BaseDir:      baseDir,
 		OrganizedDir: organizedDir,
 		OutputDir:    libraryDir,
-		TrackingFile: trackingPath,
 		CacheDir:     cacheDir,
+		Store:        db,
 		Logger:       zerolog.Nop(),
 	})
 
@@ -116,38 +108,36 @@ func TestRun_UpdatesOrganizedWhenDownloadURLChanges(t *testing.T) {
 		t.Fatalf("write dest file: %v", err)
 	}
 
-	// Tracking entry contains the new URL.
-	trackingPath := filepath.Join(cacheDir, "file_tracking.json")
-	tracking := map[string]TrackingEntry{
-		relPath: {Link: "https://real-debrid.com/d/ABC123", DownloadURL: newURL},
-	}
-	trackingBytes, err := json.Marshal(tracking)
+	db, err := store.Open(filepath.Join(cacheDir, "robofuse.db"))
 	if err != nil {
-		t.Fatalf("marshal tracking: %v", err)
+		t.Fatalf("store.Open: %v", err)
 	}
-	if err := os.WriteFile(trackingPath, trackingBytes, 0644); err != nil {
-		t.Fatalf("write tracking: %v", err)
+	defer db.Close()
+
+	// Tracking entry contains the new URL.
+	if err := db.Put(store.TrackingBucket, relPath, &TrackingEntry{
+		Link:        "https://real-debrid.com/d/ABC123",
+		DownloadURL: newURL,
+	}); err != nil {
+		t.Fatalf("seed tracking: %v", err)
 	}
 
 	// Organizer DB references the organized file with the old URL.
-	dbPath := filepath.Join(cacheDir, "organizer_db.json")
-	db := map[string]FileEntry{
-		relPath: {DestPath: relPath, RDID: "ABC123", Type: "movie", DownloadURL: oldURL},
-	}
-	dbBytes, err := json.Marshal(db)
-	if err != nil {
-		t.Fatalf("marshal db: %v", err)
-	}
-	if err := os.WriteFile(dbPath, dbBytes, 0644); err != nil {
-		t.Fatalf("write db: %v", err)
+	if err := db.Put(store.OrganizedBucket, relPath, &FileEntry{
+		DestPath:    relPath,
+		RDID:        "ABC123",
+		Type:        "movie",
+		DownloadURL: oldURL,
+	}); err != nil {
+		t.Fatalf("seed organized entry: %v", err)
 	}
 
 	org := New(Config{
 		BaseDir:      baseDir,
 		OrganizedDir: organizedDir,
 		OutputDir:    libraryDir,
-		TrackingFile: trackingPath,
 		CacheDir:     cacheDir,
+		Store:        db,
 		Logger:       zerolog.Nop(),
 	})
 
@@ -200,27 +190,28 @@ func TestRun_UsesCWDForRelativeOrganizedDir(t *testing.T) {
 		t.Fatalf("write source file: %v", err)
 	}
 
-	trackingPath := filepath.Join("cache", "file_tracking.json")
-	if err := os.MkdirAll(filepath.Dir(trackingPath), 0755); err != nil {
+	if err := os.MkdirAll("cache", 0755); err != nil {
 		t.Fatalf("mkdir cache dir: %v", err)
 	}
-	tracking := map[string]TrackingEntry{
-		relPath: {Link: "https://real-debrid.com/d/ABC123", DownloadURL: "https://new.example/stream"},
-	}
-	trackingBytes, err := json.Marshal(tracking)
+	db, err := store.Open(filepath.Join("cache", "robofuse.db"))
 	if err != nil {
-		t.Fatalf("marshal tracking: %v", err)
+		t.Fatalf("store.Open: %v", err)
 	}
-	if err := os.WriteFile(trackingPath, trackingBytes, 0644); err != nil {
-		t.Fatalf("write tracking: %v", err)
+	defer db.Close()
+
+	if err := db.Put(store.TrackingBucket, relPath, &TrackingEntry{
+		Link:        "https://real-debrid.com/d/ABC123",
+		DownloadURL: "https://new.example/stream",
+	}); err != nil {
+		t.Fatalf("seed tracking: %v", err)
 	}
 
 	org := New(Config{
 		BaseDir:      configDir,
 		OrganizedDir: "./library-organized",
 		OutputDir:    "./library",
-		TrackingFile: trackingPath,
 		CacheDir:     "./cache",
+		Store:        db,
 		Logger:       zerolog.Nop(),
 	})
 
@@ -229,7 +220,7 @@ func TestRun_UsesCWDForRelativeOrganizedDir(t *testing.T) {
 		t.Fatalf("expected 1 organized file, got new=%d", result.New)
 	}
 
-	entry, exists := org.db[relPath]
+	entry, exists := org.Get(relPath)
 	if !exists {
 		t.Fatalf("expected organizer DB entry for %s", relPath)
 	}
@@ -246,3 +237,441 @@ func TestRun_UsesCWDForRelativeOrganizedDir(t *testing.T) {
 		t.Fatalf("unexpected stat error for config directory target: %v", err)
 	}
 }
+
+func TestRun_HashDedupesIdenticalContent(t *testing.T) {
+	baseDir := t.TempDir()
+
+	libraryDir := filepath.Join(baseDir, "library")
+	organizedDir := filepath.Join(baseDir, "library-organized")
+	cacheDir := filepath.Join(baseDir, "cache")
+
+	for _, dir := range []string{libraryDir, organizedDir, cacheDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+
+	// Two Real-Debrid IDs pointing at byte-identical content, e.g. the
+	// same episode re-cached under a different torrent.
+	relPathA := filepath.Join("Some.Movie.2024.ABC123", "Some.Movie.2024.strm")
+	relPathB := filepath.Join("Some.Movie.2024.DEF456", "Some.Movie.2024.strm")
+	content := []byte("https://real-debrid.com/d/shared-content")
+
+	for _, relPath := range []string{relPathA, relPathB} {
+		full := filepath.Join(libraryDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("mkdir source dir: %v", err)
+		}
+		if err := os.WriteFile(full, content, 0644); err != nil {
+			t.Fatalf("write source file: %v", err)
+		}
+	}
+
+	db, err := store.Open(filepath.Join(cacheDir, "robofuse.db"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Put(store.TrackingBucket, relPathA, &TrackingEntry{Link: "https://real-debrid.com/d/ABC123"}); err != nil {
+		t.Fatalf("seed tracking A: %v", err)
+	}
+	if err := db.Put(store.TrackingBucket, relPathB, &TrackingEntry{Link: "https://real-debrid.com/d/DEF456"}); err != nil {
+		t.Fatalf("seed tracking B: %v", err)
+	}
+
+	org := New(Config{
+		BaseDir:       baseDir,
+		OrganizedDir:  organizedDir,
+		OutputDir:     libraryDir,
+		CacheDir:      cacheDir,
+		Store:         db,
+		Logger:        zerolog.Nop(),
+		HashAlgorithm: "sha1",
+	})
+
+	result := org.Run()
+	if result.New != 2 {
+		t.Fatalf("expected both entries organized, got new=%d errors=%d", result.New, result.Errors)
+	}
+
+	entryA, ok := org.Get(relPathA)
+	if !ok {
+		t.Fatalf("expected organized entry for %s", relPathA)
+	}
+	entryB, ok := org.Get(relPathB)
+	if !ok {
+		t.Fatalf("expected organized entry for %s", relPathB)
+	}
+	if entryA.Hash == "" || entryA.Hash != entryB.Hash {
+		t.Fatalf("expected matching recorded hashes, got %q and %q", entryA.Hash, entryB.Hash)
+	}
+
+	destA := filepath.Join(organizedDir, entryA.DestPath)
+	destB := filepath.Join(organizedDir, entryB.DestPath)
+	infoA, err := os.Stat(destA)
+	if err != nil {
+		t.Fatalf("stat destA: %v", err)
+	}
+	infoB, err := os.Stat(destB)
+	if err != nil {
+		t.Fatalf("stat destB: %v", err)
+	}
+	if !os.SameFile(infoA, infoB) {
+		t.Fatalf("expected duplicate entries to be linked to the same file on disk")
+	}
+
+	verifyResult, err := org.Verify()
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if verifyResult.Checked != 2 || len(verifyResult.Mismatches) != 0 {
+		t.Fatalf("expected 2 clean checks, got checked=%d mismatches=%v", verifyResult.Checked, verifyResult.Mismatches)
+	}
+
+	// Corrupt one of the linked files on disk and confirm Verify catches it.
+	if err := os.Remove(destA); err != nil {
+		t.Fatalf("remove destA: %v", err)
+	}
+	if err := os.WriteFile(destA, []byte("corrupted"), 0644); err != nil {
+		t.Fatalf("write corrupted destA: %v", err)
+	}
+
+	verifyResult, err = org.Verify()
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if len(verifyResult.Mismatches) != 1 || verifyResult.Mismatches[0] != entryA.DestPath {
+		t.Fatalf("expected destA reported as a mismatch, got %v", verifyResult.Mismatches)
+	}
+}
+
+func TestRun_HardlinkPlacementSharesInode(t *testing.T) {
+	baseDir := t.TempDir()
+
+	libraryDir := filepath.Join(baseDir, "library")
+	organizedDir := filepath.Join(baseDir, "library-organized")
+	cacheDir := filepath.Join(baseDir, "cache")
+
+	for _, dir := range []string{libraryDir, organizedDir, cacheDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+
+	relPath := filepath.Join("Example.2024", "Example.2024.strm")
+	sourceFullPath := filepath.Join(libraryDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(sourceFullPath), 0755); err != nil {
+		t.Fatalf("mkdir source dir: %v", err)
+	}
+	if err := os.WriteFile(sourceFullPath, []byte("https://real-debrid.com/d/ABC123"), 0644); err != nil {
+		t.Fatalf("write source file: %v", err)
+	}
+
+	db, err := store.Open(filepath.Join(cacheDir, "robofuse.db"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Put(store.TrackingBucket, relPath, &TrackingEntry{Link: "https://real-debrid.com/d/ABC123"}); err != nil {
+		t.Fatalf("seed tracking: %v", err)
+	}
+
+	org := New(Config{
+		BaseDir:       baseDir,
+		OrganizedDir:  organizedDir,
+		OutputDir:     libraryDir,
+		CacheDir:      cacheDir,
+		Store:         db,
+		Logger:        zerolog.Nop(),
+		PlacementMode: PlacementHardlink,
+	})
+
+	result := org.Run()
+	if result.New != 1 {
+		t.Fatalf("expected 1 organized file, got new=%d errors=%d", result.New, result.Errors)
+	}
+
+	entry, ok := org.Get(relPath)
+	if !ok {
+		t.Fatalf("expected organized entry for %s", relPath)
+	}
+	destFullPath := filepath.Join(organizedDir, entry.DestPath)
+
+	sourceInfo, err := os.Stat(sourceFullPath)
+	if err != nil {
+		t.Fatalf("stat source: %v", err)
+	}
+	destInfo, err := os.Stat(destFullPath)
+	if err != nil {
+		t.Fatalf("stat dest: %v", err)
+	}
+	if !os.SameFile(sourceInfo, destInfo) {
+		t.Fatalf("expected organized file to share an inode with its source")
+	}
+}
+
+func TestRun_SymlinkPlacementCleanedUpWhenSourceDisappears(t *testing.T) {
+	baseDir := t.TempDir()
+
+	libraryDir := filepath.Join(baseDir, "library")
+	organizedDir := filepath.Join(baseDir, "library-organized")
+	cacheDir := filepath.Join(baseDir, "cache")
+
+	for _, dir := range []string{libraryDir, organizedDir, cacheDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+
+	relPath := filepath.Join("Example.2024", "Example.2024.strm")
+	sourceFullPath := filepath.Join(libraryDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(sourceFullPath), 0755); err != nil {
+		t.Fatalf("mkdir source dir: %v", err)
+	}
+	if err := os.WriteFile(sourceFullPath, []byte("https://real-debrid.com/d/ABC123"), 0644); err != nil {
+		t.Fatalf("write source file: %v", err)
+	}
+
+	db, err := store.Open(filepath.Join(cacheDir, "robofuse.db"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Put(store.TrackingBucket, relPath, &TrackingEntry{Link: "https://real-debrid.com/d/ABC123"}); err != nil {
+		t.Fatalf("seed tracking: %v", err)
+	}
+
+	org := New(Config{
+		BaseDir:       baseDir,
+		OrganizedDir:  organizedDir,
+		OutputDir:     libraryDir,
+		CacheDir:      cacheDir,
+		Store:         db,
+		Logger:        zerolog.Nop(),
+		PlacementMode: PlacementSymlink,
+	})
+
+	if result := org.Run(); result.New != 1 {
+		t.Fatalf("expected 1 organized file, got new=%d errors=%d", result.New, result.Errors)
+	}
+
+	entry, ok := org.Get(relPath)
+	if !ok {
+		t.Fatalf("expected organized entry for %s", relPath)
+	}
+	destFullPath := filepath.Join(organizedDir, entry.DestPath)
+	if _, err := os.Lstat(destFullPath); err != nil {
+		t.Fatalf("expected symlink at %s, got err=%v", destFullPath, err)
+	}
+
+	// Remove the source and the tracking entry, then re-run: the organized
+	// side's symlink is now broken (its target no longer exists), but the
+	// cleanup pass should still remove it since it's keyed on the now-gone
+	// tracking entry, not on whether the dangling link resolves.
+	if err := os.Remove(sourceFullPath); err != nil {
+		t.Fatalf("remove source: %v", err)
+	}
+	if err := db.Delete(store.TrackingBucket, relPath); err != nil {
+		t.Fatalf("remove tracking entry: %v", err)
+	}
+
+	result := org.Run()
+	if result.Deleted != 1 {
+		t.Fatalf("expected 1 deleted entry, got deleted=%d", result.Deleted)
+	}
+	if _, err := os.Lstat(destFullPath); !os.IsNotExist(err) {
+		t.Fatalf("expected broken symlink to be removed, stat err=%v", err)
+	}
+	if _, err := os.Lstat(sourceFullPath); !os.IsNotExist(err) {
+		t.Fatalf("cleanup must not touch the (already-removed) source: stat err=%v", err)
+	}
+}
+
+func TestRunContext_StreamsProgressAndHonorsCancellation(t *testing.T) {
+	baseDir := t.TempDir()
+
+	libraryDir := filepath.Join(baseDir, "library")
+	organizedDir := filepath.Join(baseDir, "library-organized")
+	cacheDir := filepath.Join(baseDir, "cache")
+
+	for _, dir := range []string{libraryDir, organizedDir, cacheDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+
+	relPath := filepath.Join("Example.2024", "Example.2024.strm")
+	sourceFullPath := filepath.Join(libraryDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(sourceFullPath), 0755); err != nil {
+		t.Fatalf("mkdir source dir: %v", err)
+	}
+	if err := os.WriteFile(sourceFullPath, []byte("https://real-debrid.com/d/ABC123"), 0644); err != nil {
+		t.Fatalf("write source file: %v", err)
+	}
+
+	db, err := store.Open(filepath.Join(cacheDir, "robofuse.db"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Put(store.TrackingBucket, relPath, &TrackingEntry{Link: "https://real-debrid.com/d/ABC123"}); err != nil {
+		t.Fatalf("seed tracking: %v", err)
+	}
+
+	org := New(Config{
+		BaseDir:      baseDir,
+		OrganizedDir: organizedDir,
+		OutputDir:    libraryDir,
+		CacheDir:     cacheDir,
+		Store:        db,
+		Logger:       zerolog.Nop(),
+	})
+
+	progress := make(chan Progress, 4)
+	result, err := org.RunContext(context.Background(), progress)
+	if err != nil {
+		t.Fatalf("RunContext: %v", err)
+	}
+	if result.New != 1 {
+		t.Fatalf("expected 1 organized file, got new=%d errors=%d", result.New, result.Errors)
+	}
+
+	var events []Progress
+	for p := range progress {
+		events = append(events, p)
+	}
+	if len(events) == 0 {
+		t.Fatalf("expected at least one progress event")
+	}
+	if events[0].Phase != "organize" || events[0].CurrentPath != relPath || events[0].Total != 1 {
+		t.Fatalf("unexpected first progress event: %+v", events[0])
+	}
+
+	// A context cancelled before RunContext starts should abort before any
+	// file is organized, and report ctx.Err() rather than a nil error.
+	db2, err := store.Open(filepath.Join(cacheDir, "robofuse2.db"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	defer db2.Close()
+	if err := db2.Put(store.TrackingBucket, relPath, &TrackingEntry{Link: "https://real-debrid.com/d/ABC123"}); err != nil {
+		t.Fatalf("seed tracking: %v", err)
+	}
+
+	org2 := New(Config{
+		BaseDir:      baseDir,
+		OrganizedDir: organizedDir,
+		OutputDir:    libraryDir,
+		CacheDir:     cacheDir,
+		Store:        db2,
+		Logger:       zerolog.Nop(),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := org2.RunContext(ctx, nil); err == nil {
+		t.Fatalf("expected RunContext to report the cancellation error")
+	}
+}
+
+// TestRunContext_ConcurrentWorkersShareDestinationFolder drives the worker
+// pool with several workers over many episodes of the same show, which all
+// resolve to the same season folder under destDirLocks. Run with -race:
+// it's meant to catch lock-ordering/sync.Map misuse in the pool, not just
+// wrong counts.
+func TestRunContext_ConcurrentWorkersShareDestinationFolder(t *testing.T) {
+	baseDir := t.TempDir()
+
+	libraryDir := filepath.Join(baseDir, "library")
+	organizedDir := filepath.Join(baseDir, "library-organized")
+	cacheDir := filepath.Join(baseDir, "cache")
+
+	for _, dir := range []string{libraryDir, organizedDir, cacheDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+
+	db, err := store.Open(filepath.Join(cacheDir, "robofuse.db"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	defer db.Close()
+
+	const episodeCount = 20
+	var relPaths []string
+	for i := 1; i <= episodeCount; i++ {
+		relPath := filepath.Join(
+			fmt.Sprintf("Some.Show.S01E%02d.1080p.WEB", i),
+			fmt.Sprintf("Some.Show.S01E%02d.1080p.WEB.strm", i),
+		)
+		sourceFullPath := filepath.Join(libraryDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(sourceFullPath), 0755); err != nil {
+			t.Fatalf("mkdir source dir: %v", err)
+		}
+		url := fmt.Sprintf("https://real-debrid.com/d/EP%02d", i)
+		if err := os.WriteFile(sourceFullPath, []byte(url), 0644); err != nil {
+			t.Fatalf("write source file: %v", err)
+		}
+		if err := db.Put(store.TrackingBucket, relPath, &TrackingEntry{Link: url}); err != nil {
+			t.Fatalf("seed tracking: %v", err)
+		}
+		relPaths = append(relPaths, relPath)
+	}
+
+	org := New(Config{
+		BaseDir:      baseDir,
+		OrganizedDir: organizedDir,
+		OutputDir:    libraryDir,
+		CacheDir:     cacheDir,
+		Store:        db,
+		Logger:       zerolog.Nop(),
+		Workers:      8,
+	})
+
+	result, err := org.RunContext(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("RunContext: %v", err)
+	}
+	if result.New != episodeCount || result.Errors != 0 {
+		t.Fatalf("expected %d new entries and no errors, got new=%d errors=%d", episodeCount, result.New, result.Errors)
+	}
+
+	seasonFolders := make(map[string]struct{})
+	for _, relPath := range relPaths {
+		entry, ok := org.Get(relPath)
+		if !ok {
+			t.Fatalf("expected organized entry for %s", relPath)
+		}
+		if _, err := os.Stat(filepath.Join(organizedDir, entry.DestPath)); err != nil {
+			t.Fatalf("stat organized file for %s: %v", relPath, err)
+		}
+		seasonFolders[filepath.Dir(entry.DestPath)] = struct{}{}
+	}
+	if len(seasonFolders) != 1 {
+		t.Fatalf("expected all episodes organized into one season folder, got %v", seasonFolders)
+	}
+
+	// Re-running after every source disappears should clean up every
+	// organized entry without any worker racing the cleanup pass.
+	for _, relPath := range relPaths {
+		if err := os.Remove(filepath.Join(libraryDir, relPath)); err != nil {
+			t.Fatalf("remove source: %v", err)
+		}
+		if err := db.Delete(store.TrackingBucket, relPath); err != nil {
+			t.Fatalf("remove tracking entry: %v", err)
+		}
+	}
+
+	result, err = org.RunContext(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("RunContext: %v", err)
+	}
+	if result.Deleted != episodeCount {
+		t.Fatalf("expected %d deleted entries, got %d", episodeCount, result.Deleted)
+	}
+}
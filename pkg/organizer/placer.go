@@ -0,0 +1,150 @@
+package organizer
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// placer.go selects how an organized file is physically placed relative to
+// its source: a full byte copy (the default, and the only option portable
+// across filesystems and OSes), or a space-saving hardlink/symlink/reflink
+// for libraries where the source and organized tree share a filesystem
+// (e.g. an rclone-mounted Real-Debrid library).
+
+// PlacementMode selects organizeEntry's placement strategy.
+type PlacementMode string
+
+const (
+	PlacementCopy     PlacementMode = "copy"
+	PlacementHardlink PlacementMode = "hardlink"
+	PlacementSymlink  PlacementMode = "symlink"
+	PlacementReflink  PlacementMode = "reflink"
+)
+
+// Placer places src at dst using one particular strategy. ctx is honored
+// between chunks of a copyPlacer's copy so a cancelled RunContext can
+// abort mid-file instead of running an arbitrarily large copy to
+// completion; the link-based placers are a single syscall and only check
+// ctx before starting.
+type Placer interface {
+	Place(ctx context.Context, src, dst string) error
+}
+
+// newPlacer resolves mode to its Placer. An empty or unrecognized mode
+// falls back to a plain copy.
+func newPlacer(mode PlacementMode) Placer {
+	switch mode {
+	case PlacementHardlink:
+		return hardlinkPlacer{fallback: copyPlacer{}}
+	case PlacementSymlink:
+		return symlinkPlacer{}
+	case PlacementReflink:
+		return reflinkPlacer{fallback: copyPlacer{}}
+	default:
+		return copyPlacer{}
+	}
+}
+
+// copyPlacer places dst as a full byte-for-byte copy of src.
+type copyPlacer struct{}
+
+func (copyPlacer) Place(ctx context.Context, src, dst string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	destFile, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	buf := make([]byte, 1<<20) // 1MB chunks, checking ctx between each
+	_, err = io.CopyBuffer(destFile, ctxReader{ctx: ctx, r: sourceFile}, buf)
+	return err
+}
+
+// ctxReader wraps a Reader so io.CopyBuffer aborts promptly once ctx is
+// cancelled instead of running a large copy to completion regardless.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
+// hardlinkPlacer hard-links dst to src, falling back when the link fails -
+// most commonly because src and dst are on different filesystems, which
+// os.Link can't span.
+type hardlinkPlacer struct {
+	fallback Placer
+}
+
+func (p hardlinkPlacer) Place(ctx context.Context, src, dst string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	return p.fallback.Place(ctx, src, dst)
+}
+
+// symlinkPlacer symlinks dst to src's absolute path. Symlinks work across
+// filesystems, so there's no fallback case to cover.
+type symlinkPlacer struct{}
+
+func (symlinkPlacer) Place(ctx context.Context, src, dst string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		absSrc = src
+	}
+	return os.Symlink(absSrc, dst)
+}
+
+// reflinkPlacer clones dst from src via the filesystem's copy-on-write
+// reflink (Linux FICLONE), falling back when the filesystem doesn't
+// support it (e.g. not btrfs/XFS/overlayfs, or a cross-filesystem pair) or
+// the build isn't Linux at all.
+type reflinkPlacer struct {
+	fallback Placer
+}
+
+func (p reflinkPlacer) Place(ctx context.Context, src, dst string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := reflinkFile(src, dst); err == nil {
+		return nil
+	}
+	return p.fallback.Place(ctx, src, dst)
+}
@@ -0,0 +1,34 @@
+package organizer
+
+import "sync"
+
+// pool.go provides the striped locking RunContext's worker pool uses to
+// serialize operations that touch the same destination directory, while
+// leaving unrelated directories free to proceed concurrently.
+
+// dirLocker hands out one *sync.Mutex per key, created lazily, so callers
+// can serialize access to a destination directory without pre-declaring
+// every directory a run might touch.
+type dirLocker struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newDirLocker() *dirLocker {
+	return &dirLocker{locks: make(map[string]*sync.Mutex)}
+}
+
+// Lock locks key's mutex (creating it on first use) and returns the
+// matching unlock function.
+func (d *dirLocker) Lock(key string) func() {
+	d.mu.Lock()
+	m, ok := d.locks[key]
+	if !ok {
+		m = &sync.Mutex{}
+		d.locks[key] = m
+	}
+	d.mu.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
@@ -0,0 +1,310 @@
+package organizer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/robofuse/robofuse/internal/console"
+	"github.com/robofuse/robofuse/internal/store"
+	"go.etcd.io/bbolt"
+)
+
+// progress.go adds a streaming, cancellable variant of Run for large
+// libraries (e.g. right after a big Real-Debrid backfill) where a caller
+// wants to observe progress or abort mid-sweep instead of waiting for a
+// single opaque Result.
+
+// Progress describes one step of a RunContext sweep.
+type Progress struct {
+	// Phase is "organize" while walking tracked files, or "cleanup" while
+	// removing stale organized entries.
+	Phase       string `json:"phase"`
+	CurrentPath string `json:"current_path"`
+	Index       int    `json:"index"`
+	Total       int    `json:"total"`
+	// BytesCopied is the source file's size once it's been placed, or 0
+	// for an entry that was skipped or only linked to an existing copy.
+	BytesCopied int64 `json:"bytes_copied,omitempty"`
+}
+
+// publishProgress sends p on progress without blocking the caller when
+// nobody's reading fast enough; a stalled UI shouldn't stall the sweep.
+func publishProgress(progress chan<- Progress, p Progress) {
+	if progress == nil {
+		return
+	}
+	select {
+	case progress <- p:
+	default:
+	}
+}
+
+// RenderProgress reads events off progress, rendering them to a terminal
+// bar via internal/console when attached to a TTY (falling back to
+// periodic log lines otherwise, per ProgressManager's own behavior).
+// Intended to run in its own goroutine alongside RunContext:
+//
+//	progress := make(chan organizer.Progress)
+//	go organizer.RenderProgress(progress)
+//	result, err := org.RunContext(ctx, progress)
+func RenderProgress(progress <-chan Progress) {
+	mgr := console.NewProgressManager()
+	defer mgr.Stop()
+
+	var bar *console.Bar
+	var lastIndex int
+	for p := range progress {
+		if bar == nil && p.Total > 0 {
+			bar = mgr.AddBar("organizing", int64(p.Total))
+		}
+		if bar == nil {
+			continue
+		}
+		if p.Index > lastIndex {
+			bar.Add(int64(p.Index - lastIndex))
+			lastIndex = p.Index
+		}
+	}
+	if bar != nil {
+		bar.Done()
+	}
+}
+
+// Run executes a full organization sweep and blocks until it's done. It's
+// a convenience wrapper around RunContext for callers that don't need
+// progress reporting or cancellation.
+func (o *Organizer) Run() Result {
+	result, _ := o.RunContext(context.Background(), nil)
+	return result
+}
+
+// organizeTask is one tracking-bucket entry queued for RunContext's
+// worker pool.
+type organizeTask struct {
+	relPath string
+	meta    TrackingEntry
+}
+
+// RunContext is Run with a cancellable context and an optional progress
+// channel: ctx is checked before every task starts and between copy
+// chunks inside it, so a caller can abort a sweep over a library with
+// thousands of backfilled files without waiting for it to finish.
+// progress may be nil, in which case events are simply dropped.
+// RunContext closes progress before returning.
+//
+// Tracked files are processed concurrently across o.workers goroutines
+// (see Config.Workers), since hashing and copying each file dominates a
+// large sweep's wall-clock time far more than the bucket bookkeeping
+// around it.
+func (o *Organizer) RunContext(ctx context.Context, progress chan<- Progress) (Result, error) {
+	if progress != nil {
+		defer close(progress)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	if _, err := store.MigrateJSONFile(o.store, o.legacyTracking, store.TrackingBucket); err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to migrate legacy file_tracking.json")
+	}
+	if _, err := store.MigrateJSONFile(o.store, o.legacyDBPath, store.OrganizedBucket); err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to migrate legacy organizer_db.json")
+	}
+
+	// Snapshot both buckets up front: tasks are dispatched to worker
+	// goroutines that must not touch the shared *bbolt.Tx concurrently, so
+	// they work off of this read-only view instead (prevEntries is never
+	// written to after this point, so concurrent reads of it are safe).
+	var tasks []organizeTask
+	prevEntries := make(map[string]FileEntry)
+
+	err := o.store.View(func(tx *bbolt.Tx) error {
+		tracking := store.Bucket(tx, store.TrackingBucket)
+		if err := tracking.ForEach(func(k, v []byte) error {
+			relPath := string(k)
+
+			var meta TrackingEntry
+			if err := json.Unmarshal(v, &meta); err != nil {
+				o.logger.Warn().Err(err).Str("path", relPath).Msg("Skipping malformed tracking entry")
+				return nil
+			}
+			tasks = append(tasks, organizeTask{relPath: relPath, meta: meta})
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		organized := store.Bucket(tx, store.OrganizedBucket)
+		return organized.ForEach(func(k, v []byte) error {
+			var entry FileEntry
+			if err := json.Unmarshal(v, &entry); err == nil {
+				prevEntries[string(k)] = entry
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		o.logger.Error().Err(err).Msg("Failed to read organizer state")
+		return Result{}, err
+	}
+
+	total := len(tasks)
+
+	var (
+		processed, newCount, skipped, errCount, index int64
+		newState                                      sync.Map // relPath -> FileEntry
+		currentSourcePaths                            sync.Map // relPath -> struct{}
+		hashIndex                                      sync.Map // "algo|hash" -> destRelPath, filled in as workers place files
+	)
+
+	sem := make(chan struct{}, o.workers)
+	var wg sync.WaitGroup
+
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(t organizeTask) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			sourceFullPath := filepath.Join(o.libraryDir, t.relPath)
+			if !fileExists(sourceFullPath) {
+				return
+			}
+			currentSourcePaths.Store(t.relPath, struct{}{})
+			atomic.AddInt64(&processed, 1)
+
+			prevEntry, hadPrev := prevEntries[t.relPath]
+			entry, skip, err := o.resolveEntry(ctx, t.relPath, t.meta, prevEntry, hadPrev, func(hashVal, algo string) (string, bool) {
+				if destRelPath, ok := hashIndex.Load(algo + "|" + hashVal); ok {
+					return destRelPath.(string), true
+				}
+				return o.findDuplicateDestInSnapshot(prevEntries, hashVal, algo, t.relPath)
+			}, func(hashVal, algo, destRelPath string) {
+				hashIndex.Store(algo+"|"+hashVal, destRelPath)
+			})
+			switch {
+			case err != nil:
+				atomic.AddInt64(&errCount, 1)
+			case skip:
+				atomic.AddInt64(&skipped, 1)
+			default:
+				newState.Store(t.relPath, entry)
+				atomic.AddInt64(&newCount, 1)
+			}
+
+			var bytesCopied int64
+			if info, statErr := os.Stat(sourceFullPath); statErr == nil {
+				bytesCopied = info.Size()
+			}
+			publishProgress(progress, Progress{
+				Phase:       "organize",
+				CurrentPath: t.relPath,
+				Index:       int(atomic.AddInt64(&index, 1)),
+				Total:       total,
+				BytesCopied: bytesCopied,
+			})
+		}(t)
+	}
+	wg.Wait()
+
+	result := Result{
+		Processed: int(processed),
+		New:       int(newCount),
+		Skipped:   int(skipped),
+		Errors:    int(errCount),
+	}
+
+	if err := ctx.Err(); err != nil {
+		o.logger.Warn().Msg("Organizer run cancelled")
+		return result, err
+	}
+
+	// Persist every worker's decision in one serialized transaction; bbolt
+	// writes aren't safe to interleave across goroutines, so the pool
+	// above only ever stages entries into newState.
+	err = o.store.Update(func(tx *bbolt.Tx) error {
+		organized := store.Bucket(tx, store.OrganizedBucket)
+		var putErr error
+		newState.Range(func(k, v interface{}) bool {
+			relPath := k.(string)
+			data, err := json.Marshal(v.(FileEntry))
+			if err != nil {
+				o.logger.Error().Err(err).Str("path", relPath).Msg("Failed to encode organized entry")
+				return true
+			}
+			if err := organized.Put([]byte(relPath), data); err != nil {
+				putErr = err
+				return false
+			}
+			return true
+		})
+		return putErr
+	})
+	if err != nil {
+		o.logger.Error().Err(err).Msg("Failed to persist organized entries")
+		return result, err
+	}
+
+	// Cleanup deleted files: anything in "organized" whose source file is
+	// no longer tracked gets its output removed and its entry dropped.
+	// This only runs once every worker has drained, so it never races a
+	// worker that's still deciding whether to write into a directory
+	// cleanup is about to remove.
+	err = o.store.Update(func(tx *bbolt.Tx) error {
+		organized := store.Bucket(tx, store.OrganizedBucket)
+		var stale [][]byte
+
+		c := organized.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			relPath := string(k)
+			if _, ok := currentSourcePaths.Load(relPath); ok {
+				continue
+			}
+			stale = append(stale, append([]byte(nil), k...))
+		}
+
+		for i, k := range stale {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			deleted, err := o.removeOrganized(organized, string(k))
+			if err != nil {
+				return err
+			}
+			if deleted {
+				result.Deleted++
+			}
+			publishProgress(progress, Progress{
+				Phase:       "cleanup",
+				CurrentPath: string(k),
+				Index:       i + 1,
+				Total:       len(stale),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			o.logger.Warn().Msg("Organizer cleanup cancelled")
+			return result, ctx.Err()
+		}
+		o.logger.Error().Err(err).Msg("Failed to clean up stale organized entries")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,33 @@
+package organizer
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflinkFile clones dst from src via the Linux FICLONE ioctl, a
+// copy-on-write clone supported by btrfs, XFS (reflink=1), and overlayfs on
+// a supporting lower. It fails (and the caller falls back to a regular
+// copy) on filesystems without reflink support or when src and dst cross a
+// filesystem boundary.
+func reflinkFile(src, dst string) error {
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	destFile, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close()
+
+	if err := unix.IoctlFileClone(int(destFile.Fd()), int(sourceFile.Fd())); err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package organizer
+
+import "fmt"
+
+// reflinkFile is unsupported outside Linux; reflinkPlacer's caller falls
+// back to a regular copy.
+func reflinkFile(src, dst string) error {
+	return fmt.Errorf("organizer: reflink is only supported on linux")
+}
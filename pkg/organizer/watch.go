@@ -0,0 +1,184 @@
+package organizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/robofuse/robofuse/internal/store"
+	"go.etcd.io/bbolt"
+)
+
+// watch.go is an incremental alternative to Run's full sweep: a long-lived
+// fsnotify watch on the library directory that reconciles just the path
+// that changed, instead of rescanning every tracked file on a fixed
+// schedule.
+
+// watchDebounce coalesces the burst of CREATE/WRITE/REMOVE events a single
+// file rewrite tends to produce into one reconcile per path.
+const watchDebounce = 2 * time.Second
+
+// Watch runs until ctx is cancelled, applying per-file organizer updates
+// as files change under the library directory. fallbackInterval is the
+// period between full Run sweeps, which act as a safety net for anything
+// the watch missed - a dropped fsnotify event, or a tracking update with
+// no filesystem event of its own.
+func (o *Organizer) Watch(ctx context.Context, fallbackInterval time.Duration) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("organizer: starting watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := o.addRecursive(watcher, o.libraryDir); err != nil {
+		return fmt.Errorf("organizer: watching %s: %w", o.libraryDir, err)
+	}
+
+	// Sweep once up front so the organized tree reflects reality before
+	// the first event arrives.
+	o.RunContext(ctx, nil)
+
+	fallback := time.NewTicker(fallbackInterval)
+	defer fallback.Stop()
+
+	deb := newDebouncer(watchDebounce, func(path string) { o.reconcilePath(ctx, path) })
+	defer deb.stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := o.addRecursive(watcher, event.Name); err != nil {
+						o.logger.Warn().Err(err).Str("dir", event.Name).Msg("Failed to watch new directory")
+					}
+					continue
+				}
+			}
+
+			if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				deb.trigger(event.Name)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			o.logger.Warn().Err(err).Msg("Watcher error")
+
+		case <-fallback.C:
+			o.logger.Debug().Msg("Running fallback organizer sweep")
+			o.RunContext(ctx, nil)
+		}
+	}
+}
+
+// addRecursive adds dir and all of its subdirectories to watcher, since
+// fsnotify only watches the directory it's given, not its descendants.
+func (o *Organizer) addRecursive(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// reconcilePath applies whatever changed at fullPath: a missing file
+// drops its organized mirror, an existing one is organized against its
+// current tracking entry (if any).
+func (o *Organizer) reconcilePath(ctx context.Context, fullPath string) {
+	relPath, err := filepath.Rel(o.libraryDir, fullPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return
+	}
+
+	err = o.store.Update(func(tx *bbolt.Tx) error {
+		organized := store.Bucket(tx, store.OrganizedBucket)
+
+		if !fileExists(fullPath) {
+			_, err := o.removeOrganized(organized, relPath)
+			return err
+		}
+
+		tracking := store.Bucket(tx, store.TrackingBucket)
+		raw := tracking.Get([]byte(relPath))
+		if raw == nil {
+			// Not a tracked file - nothing for the organizer to do.
+			return nil
+		}
+
+		var meta TrackingEntry
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil
+		}
+
+		return o.organizeEntry(ctx, organized, relPath, meta, &Result{})
+	})
+	if err != nil {
+		o.logger.Error().Err(err).Str("path", relPath).Msg("Failed to reconcile watched path")
+	}
+}
+
+// debouncer delays fn(path) until path has been quiet for delay, so a
+// burst of events against the same file collapses into one call.
+type debouncer struct {
+	mu     sync.Mutex
+	delay  time.Duration
+	fn     func(path string)
+	timers map[string]*time.Timer
+}
+
+func newDebouncer(delay time.Duration, fn func(path string)) *debouncer {
+	return &debouncer{
+		delay:  delay,
+		fn:     fn,
+		timers: make(map[string]*time.Timer),
+	}
+}
+
+func (d *debouncer) trigger(path string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if t, ok := d.timers[path]; ok {
+		t.Reset(d.delay)
+		return
+	}
+
+	d.timers[path] = time.AfterFunc(d.delay, func() {
+		d.mu.Lock()
+		delete(d.timers, path)
+		d.mu.Unlock()
+		d.fn(path)
+	})
+}
+
+// stop cancels every pending timer, for a clean shutdown.
+func (d *debouncer) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, t := range d.timers {
+		t.Stop()
+	}
+}
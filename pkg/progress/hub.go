@@ -0,0 +1,67 @@
+// Package progress fans out structured progress events from long-running
+// batches (e.g. unrestricting a page of links) to anyone watching, such as
+// the RPC status socket, without coupling publishers to a transport.
+package progress
+
+import (
+	"sync"
+	"time"
+)
+
+// hub.go implements a simple non-blocking pub-sub fan-out.
+
+// Event is a single structured progress update.
+type Event struct {
+	Phase      string    `json:"phase"`
+	Completed  int       `json:"completed"`
+	Total      int       `json:"total"`
+	Item       string    `json:"item,omitempty"`
+	ETASeconds float64   `json:"eta_seconds,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Hub fans a published Event out to every active subscriber. A subscriber
+// whose buffer is full has the event dropped rather than blocking Publish.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// NewHub creates an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[chan Event]struct{})}
+}
+
+// Subscribe registers a new subscriber, returning its event channel and a
+// cancel func the caller must invoke once it stops reading.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 32)
+
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		if _, ok := h.subs[ch]; ok {
+			delete(h.subs, ch)
+			close(ch)
+		}
+		h.mu.Unlock()
+	}
+
+	return ch, cancel
+}
+
+// Publish fans event out to every current subscriber.
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package realdebrid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/request"
+	"github.com/robofuse/robofuse/pkg/worker"
+)
+
+// batch.go fans GetTorrentInfo out across a bounded worker pool so callers
+// with large libraries don't serialize one request per torrent. Every
+// request still goes through torrentsClient, so the pool's concurrency
+// doesn't bypass the 250 req/min limiter Real-Debrid enforces - it just
+// lets that budget be spent by many torrents in flight instead of one.
+
+const (
+	batchMaxAttempts = 5
+	batchBaseBackoff = 500 * time.Millisecond
+	batchMaxBackoff  = 30 * time.Second
+)
+
+// GetTorrentInfosBatch fetches info for each of ids concurrently, capped at
+// concurrency in-flight requests. A torrent that fails after retrying is
+// omitted from the result rather than failing the whole batch; the error
+// return only fires if every torrent failed.
+func (c *Client) GetTorrentInfosBatch(ids []string, concurrency int) (map[string]*TorrentInfo, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	pool := worker.NewPool(concurrency)
+
+	var mu sync.Mutex
+	results := make(map[string]*TorrentInfo, len(ids))
+	var errs []error
+
+	for _, id := range ids {
+		id := id
+		pool.Submit(func() {
+			info, err := c.fetchTorrentInfoWithBackoff(id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("torrent %s: %w", id, err))
+				return
+			}
+			results[id] = info
+		})
+	}
+	pool.Wait()
+
+	if len(results) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(errs) > 0 {
+		c.logger.Warn().
+			Int("succeeded", len(results)).
+			Int("failed", len(errs)).
+			Msg("Some torrent info fetches failed in batch")
+	}
+
+	return results, nil
+}
+
+// fetchTorrentInfoWithBackoff fetches one torrent's info, retrying on
+// HTTP 429/503 up to batchMaxAttempts times. It backs off for the
+// duration the server's Retry-After header asks for, falling back to
+// exponential backoff with jitter when the header is absent.
+func (c *Client) fetchTorrentInfoWithBackoff(torrentID string) (*TorrentInfo, error) {
+	backoff := batchBaseBackoff
+
+	for attempt := 1; attempt <= batchMaxAttempts; attempt++ {
+		url := fmt.Sprintf("%s/torrents/info/%s", c.Host, torrentID)
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+
+		resp, err := c.torrentsClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetching torrent info: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			retryAfter := resp.Header.Get("Retry-After")
+			resp.Body.Close()
+
+			if attempt == batchMaxAttempts {
+				return nil, fmt.Errorf("rate limited (status %d) after %d attempts", resp.StatusCode, attempt)
+			}
+
+			wait := retryDelay(retryAfter, backoff)
+			c.logger.Debug().
+				Str("torrent", torrentID).
+				Dur("wait", wait).
+				Int("attempt", attempt).
+				Msg("Backing off torrent info fetch")
+			time.Sleep(wait)
+
+			backoff *= 2
+			if backoff > batchMaxBackoff {
+				backoff = batchMaxBackoff
+			}
+			continue
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return nil, request.TorrentNotFoundError
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(body))
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+
+		var info TorrentInfo
+		if err := json.Unmarshal(body, &info); err != nil {
+			return nil, fmt.Errorf("parsing torrent info: %w", err)
+		}
+		return &info, nil
+	}
+
+	return nil, fmt.Errorf("exceeded retry attempts for torrent %s", torrentID)
+}
+
+// retryDelay parses a Retry-After header (seconds form) and adds jitter,
+// falling back to backoff with jitter when the header is missing or
+// unparseable.
+func retryDelay(retryAfter string, backoff time.Duration) time.Duration {
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil && secs >= 0 {
+			return withJitter(time.Duration(secs) * time.Second)
+		}
+	}
+	return withJitter(backoff)
+}
+
+// withJitter adds up to +25% random jitter to d.
+func withJitter(d time.Duration) time.Duration {
+	return d + time.Duration(rand.Int63n(int64(d/4+1)))
+}
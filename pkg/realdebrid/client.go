@@ -3,10 +3,12 @@ package realdebrid
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/robofuse/robofuse/internal/config"
 	"github.com/robofuse/robofuse/internal/logger"
 	"github.com/robofuse/robofuse/internal/request"
+	"github.com/robofuse/robofuse/pkg/metrics"
 	"github.com/rs/zerolog"
 	"golang.org/x/time/rate"
 )
@@ -19,8 +21,9 @@ type Client struct {
 	APIKey string
 
 	// HTTP clients with different rate limiters
-	generalClient  *request.Client
-	torrentsClient *request.Client
+	generalClient   *request.Client
+	torrentsClient  *request.Client
+	downloadsClient *request.Client
 
 	logger zerolog.Logger
 	config *config.Config
@@ -28,8 +31,26 @@ type Client struct {
 	mu sync.RWMutex
 }
 
-// New creates a new Real-Debrid client
-func New(cfg *config.Config) *Client {
+// pacerTimings derives a Pacer's starting point, ceiling, and decay time
+// constant from a requests-per-minute limit, so the pacer starts out paced
+// at roughly the configured steady-state rate and backs off from there.
+func pacerTimings(requestsPerMinute int) (minSleep, maxSleep, decayConst time.Duration) {
+	if requestsPerMinute <= 0 {
+		requestsPerMinute = 60
+	}
+
+	minSleep = time.Minute / time.Duration(requestsPerMinute)
+	if minSleep <= 0 {
+		minSleep = time.Millisecond
+	}
+	maxSleep = minSleep * 30
+	decayConst = minSleep * 10
+
+	return minSleep, maxSleep, decayConst
+}
+
+// New creates a new Real-Debrid client. reg may be nil to disable metrics.
+func New(cfg *config.Config, reg *metrics.Registry) *Client {
 	log := logger.New("realdebrid")
 
 	headers := map[string]string{
@@ -39,6 +60,7 @@ func New(cfg *config.Config) *Client {
 	// Create rate limiters
 	generalRL := request.ParseRateLimitInt(cfg.GeneralRateLimit)
 	torrentsRL := request.ParseRateLimitInt(cfg.TorrentsRateLimit)
+	downloadsRL := request.ParseRateLimitInt(cfg.DownloadsRateLimit)
 
 	// Fallback if parsing fails
 	if generalRL == nil {
@@ -47,32 +69,64 @@ func New(cfg *config.Config) *Client {
 	if torrentsRL == nil {
 		torrentsRL = rate.NewLimiter(rate.Limit(0.4), 1) // ~25 req/min
 	}
+	if downloadsRL == nil {
+		downloadsRL = rate.NewLimiter(rate.Limit(1.0/6.0), 1) // ~10 req/min
+	}
+
+	metricsOptions := func(endpoint string) []request.ClientOption {
+		if reg == nil {
+			return []request.ClientOption{request.WithEndpointName(endpoint)}
+		}
+		return []request.ClientOption{
+			request.WithEndpointName(endpoint),
+			request.WithLatencyObserver(reg.ObserveAPILatency),
+			request.WithRateLimitObserver(reg.ObserveRateLimitWait),
+		}
+	}
+
+	generalMin, generalMax, generalDecay := pacerTimings(cfg.GeneralRateLimit)
+	torrentsMin, torrentsMax, torrentsDecay := pacerTimings(cfg.TorrentsRateLimit)
+	downloadsMin, downloadsMax, downloadsDecay := pacerTimings(cfg.DownloadsRateLimit)
 
 	// General client for most endpoints
-	generalClient := request.New(
+	generalClient := request.New(append([]request.ClientOption{
 		request.WithHeaders(headers),
 		request.WithRateLimiter(generalRL),
+		request.WithPacer(generalMin, generalMax, generalDecay),
 		request.WithLogger(log),
 		request.WithMaxRetries(5),
 		request.WithRetryableStatus(429, 502, 503),
-	)
+	}, metricsOptions("general")...)...)
 
 	// Torrents client with stricter rate limiting
-	torrentsClient := request.New(
+	torrentsClient := request.New(append([]request.ClientOption{
 		request.WithHeaders(headers),
 		request.WithRateLimiter(torrentsRL),
+		request.WithPacer(torrentsMin, torrentsMax, torrentsDecay),
 		request.WithLogger(log),
 		request.WithMaxRetries(5),
 		request.WithRetryableStatus(429, 502, 503),
-	)
+	}, metricsOptions("torrents")...)...)
+
+	// Downloads client for large-file range-request fetches (cache mode);
+	// retries cover transient 5xx as well as 429.
+	downloadsClient := request.New(append([]request.ClientOption{
+		request.WithHeaders(headers),
+		request.WithRateLimiter(downloadsRL),
+		request.WithPacer(downloadsMin, downloadsMax, downloadsDecay),
+		request.WithLogger(log),
+		request.WithMaxRetries(5),
+		request.WithRetryableStatus(429, 500, 502, 503, 504),
+	}, metricsOptions("downloads")...)...)
 
 	return &Client{
-		Host:           "https://api.real-debrid.com/rest/1.0",
-		APIKey:         cfg.Token,
-		generalClient:  generalClient,
-		torrentsClient: torrentsClient,
-		logger:         log,
-		config:         cfg,
+		Host:            "https://api.real-debrid.com/rest/1.0",
+		APIKey:          cfg.Token,
+		generalClient:   generalClient,
+		torrentsClient:  torrentsClient,
+		downloadsClient: downloadsClient,
+		logger:          log,
+		config:          cfg,
 	}
 }
 
@@ -80,3 +134,10 @@ func New(cfg *config.Config) *Client {
 func (c *Client) GetLogger() zerolog.Logger {
 	return c.logger
 }
+
+// DownloadsClient returns the rate-limited HTTP client used for large-file
+// range-request fetches, so callers like pkg/fetch share its limiter and
+// retry policy instead of hammering Real-Debrid with their own client.
+func (c *Client) DownloadsClient() *request.Client {
+	return c.downloadsClient
+}
@@ -0,0 +1,93 @@
+package realdebrid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// ingest.go adds a .torrent-file upload path and an instant-availability
+// check alongside the existing magnet-only AddMagnet, so robofuse can
+// accept arbitrary feeds (RSS, *arr) without wasting RD slots on torrents
+// it can't instantly cache.
+
+// AddTorrentFile uploads a bencoded .torrent file's raw bytes to
+// Real-Debrid and returns the resulting torrent ID.
+func (c *Client) AddTorrentFile(data []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "upload.torrent")
+	if err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/torrents/addTorrent", c.Host)
+	req, err := http.NewRequest(http.MethodPut, url, &body)
+	if err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.torrentsClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("adding torrent: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result AddMagnetResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("parsing response: %w", err)
+	}
+
+	c.logger.Info().Str("id", result.ID).Msg("Added torrent file")
+	return result.ID, nil
+}
+
+// CheckAvailability queries /torrents/instantAvailability for hash and
+// reports whether Real-Debrid already has a cached copy, so callers can
+// skip AddMagnet for torrents that would just sit uncached.
+func (c *Client) CheckAvailability(hash string) (bool, error) {
+	url := fmt.Sprintf("%s/torrents/instantAvailability/%s", c.Host, hash)
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+
+	resp, err := c.torrentsClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("checking availability: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var result AvailabilityResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return false, fmt.Errorf("parsing response: %w", err)
+	}
+
+	hoster, ok := result[hash]
+	return ok && len(hoster.Rd) > 0, nil
+}
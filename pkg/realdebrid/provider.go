@@ -0,0 +1,135 @@
+package realdebrid
+
+import "github.com/robofuse/robofuse/internal/debrid"
+
+// provider.go adapts Client onto debrid.Provider so a Real-Debrid account
+// can sit behind the same aggregation point as other backends.
+
+// Adapter wraps a Client to satisfy debrid.Provider.
+type Adapter struct {
+	*Client
+}
+
+// NewAdapter wraps c as a debrid.Provider.
+func NewAdapter(c *Client) *Adapter {
+	return &Adapter{Client: c}
+}
+
+// Name identifies this provider as "real-debrid".
+func (a *Adapter) Name() string {
+	return "real-debrid"
+}
+
+// GetTorrents implements debrid.Provider, hydrating files so Torrent.Files
+// is always populated for aggregated callers.
+func (a *Adapter) GetTorrents() ([]*debrid.Torrent, []*debrid.Torrent, error) {
+	downloaded, dead, err := a.Client.GetTorrents(WithFiles())
+	if err != nil {
+		return nil, nil, err
+	}
+	return toDebridTorrents(downloaded), toDebridTorrents(dead), nil
+}
+
+// GetTorrentInfo implements debrid.Provider.
+func (a *Adapter) GetTorrentInfo(torrentID string) (*debrid.Torrent, error) {
+	info, err := a.Client.GetTorrentInfo(torrentID)
+	if err != nil {
+		return nil, err
+	}
+	return torrentInfoToDebrid(info), nil
+}
+
+// WaitForDownload implements debrid.Provider.
+func (a *Adapter) WaitForDownload(torrentID string, maxAttempts int) (*debrid.Torrent, error) {
+	info, err := a.Client.WaitForDownload(torrentID, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	return torrentInfoToDebrid(info), nil
+}
+
+// UnrestrictLink implements debrid.Provider.
+func (a *Adapter) UnrestrictLink(link string) (*debrid.Download, error) {
+	d, err := a.Client.UnrestrictLink(link)
+	if err != nil {
+		return nil, err
+	}
+	return toDebridDownload(d), nil
+}
+
+// GetDownloads implements debrid.Provider.
+func (a *Adapter) GetDownloads() ([]*debrid.Download, error) {
+	downloads, err := a.Client.GetDownloads()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*debrid.Download, len(downloads))
+	for i, d := range downloads {
+		out[i] = toDebridDownload(d)
+	}
+	return out, nil
+}
+
+// AddTorrentFile implements debrid.Provider.
+func (a *Adapter) AddTorrentFile(data []byte) (string, error) {
+	return a.Client.AddTorrentFile(data)
+}
+
+func toDebridDownload(d *Download) *debrid.Download {
+	return &debrid.Download{
+		ID:         d.ID,
+		Filename:   d.Filename,
+		MimeType:   d.MimeType,
+		Filesize:   d.Filesize,
+		Link:       d.Link,
+		Host:       d.Host,
+		Chunks:     d.Chunks,
+		Download:   d.Download,
+		Streamable: d.Streamable,
+		Generated:  d.Generated,
+	}
+}
+
+func toDebridTorrents(in []*Torrent) []*debrid.Torrent {
+	if in == nil {
+		return nil
+	}
+	out := make([]*debrid.Torrent, len(in))
+	for i, t := range in {
+		out[i] = &debrid.Torrent{
+			ID:       t.ID,
+			Filename: t.Filename,
+			Hash:     t.Hash,
+			Bytes:    t.Bytes,
+			Status:   t.Status,
+			Progress: t.Progress,
+			Links:    t.Links,
+			Files:    toDebridFiles(t.Files),
+		}
+	}
+	return out
+}
+
+func torrentInfoToDebrid(info *TorrentInfo) *debrid.Torrent {
+	return &debrid.Torrent{
+		ID:       info.ID,
+		Filename: info.Filename,
+		Hash:     info.Hash,
+		Bytes:    info.Bytes,
+		Status:   info.Status,
+		Progress: info.Progress,
+		Links:    info.Links,
+		Files:    toDebridFiles(info.Files),
+	}
+}
+
+func toDebridFiles(in []File) []debrid.File {
+	if in == nil {
+		return nil
+	}
+	out := make([]debrid.File, len(in))
+	for i, f := range in {
+		out[i] = debrid.File{ID: f.ID, Path: f.Path, Bytes: f.Bytes, Selected: f.Selected}
+	}
+	return out
+}
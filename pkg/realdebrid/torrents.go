@@ -12,9 +12,30 @@ import (
 	"github.com/robofuse/robofuse/internal/request"
 )
 
+// GetTorrentsOption configures optional GetTorrents behavior.
+type GetTorrentsOption func(*getTorrentsOptions)
+
+type getTorrentsOptions struct {
+	withFiles bool
+}
+
+// WithFiles hydrates each returned downloaded Torrent's Files via
+// GetTorrentInfosBatch instead of leaving it for the caller to fetch
+// one-by-one.
+func WithFiles() GetTorrentsOption {
+	return func(o *getTorrentsOptions) {
+		o.withFiles = true
+	}
+}
+
 // GetTorrents fetches all torrents with pagination (limit=100 to ensure links are returned)
 // Returns: downloaded torrents, dead torrents, error
-func (c *Client) GetTorrents() ([]*Torrent, []*Torrent, error) {
+func (c *Client) GetTorrents(opts ...GetTorrentsOption) ([]*Torrent, []*Torrent, error) {
+	var options getTorrentsOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	c.logger.Debug().Msg("Fetching all torrents with pagination...")
 
 	var allTorrents []*Torrent
@@ -94,9 +115,36 @@ func (c *Client) GetTorrents() ([]*Torrent, []*Torrent, error) {
 		Int("dead", len(dead)).
 		Msg("Torrents fetched and filtered")
 
+	if options.withFiles && len(downloaded) > 0 {
+		c.hydrateFiles(downloaded)
+	}
+
 	return downloaded, dead, nil
 }
 
+// hydrateFiles fills in Files for each torrent using a concurrent batch
+// fetch, so callers no longer have to serialize N GetTorrentInfo calls.
+// Torrents whose info couldn't be fetched are left with no Files and
+// logged, rather than failing the whole GetTorrents call.
+func (c *Client) hydrateFiles(torrents []*Torrent) {
+	ids := make([]string, len(torrents))
+	for i, t := range torrents {
+		ids[i] = t.ID
+	}
+
+	infos, err := c.GetTorrentInfosBatch(ids, c.config.ConcurrentRequests)
+	if err != nil {
+		c.logger.Warn().Err(err).Msg("Failed to batch-fetch torrent files")
+		return
+	}
+
+	for _, t := range torrents {
+		if info, ok := infos[t.ID]; ok {
+			t.Files = info.Files
+		}
+	}
+}
+
 // GetTorrentInfo fetches detailed info for a specific torrent
 func (c *Client) GetTorrentInfo(torrentID string) (*TorrentInfo, error) {
 	url := fmt.Sprintf("%s/torrents/info/%s", c.Host, torrentID)
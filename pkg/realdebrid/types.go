@@ -186,6 +186,15 @@ type STRMCandidate struct {
 	TorrentFolder string // Name of folder (from torrent filename)
 	Filename      string // Name of file (from download filename)
 	DownloadURL   string // Direct download URL (goes inside .strm file)
-	Link          string // Original RD link (for matching)
+	Link          string // Original link (for matching)
 	Filesize      int64
+	// Provider is the debrid backend this candidate came from (e.g.
+	// "real-debrid", "alldebrid"), stamped onto tracked entries so a run
+	// aggregating more than one account can tell them apart.
+	Provider string
+	// FallbackURLs lists alternate URLs for the same file (e.g. other
+	// hosters returned alongside DownloadURL), written into M3U8 output
+	// (config.OutputFormat == "m3u") as extra entries a player can fail
+	// over to if the primary link 404s. Unused by the plain "strm" format.
+	FallbackURLs []string
 }
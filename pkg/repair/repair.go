@@ -4,31 +4,54 @@ import (
 	"fmt"
 
 	"github.com/robofuse/robofuse/internal/config"
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/internal/diskspace"
 	"github.com/robofuse/robofuse/internal/logger"
-	"github.com/robofuse/robofuse/pkg/realdebrid"
+	"github.com/robofuse/robofuse/internal/quota"
 	"github.com/rs/zerolog"
 )
 
 // repair.go re-adds dead torrents using cached magnet data when possible.
 
-// Service handles torrent repair operations
+// Service handles torrent repair operations against a single debrid
+// provider account.
 type Service struct {
-	rd     *realdebrid.Client
-	config *config.Config
-	logger zerolog.Logger
+	provider  debrid.Provider
+	config    *config.Config
+	diskGuard *diskspace.Guard
+	quota     *quota.Tracker
+	logger    zerolog.Logger
 }
 
-// New creates a new repair service
-func New(rd *realdebrid.Client, cfg *config.Config) *Service {
+// New creates a new repair service for the given provider account.
+// diskGuard withholds reinserts while the host is low on disk space, the
+// same way strm.Service.Sync withholds new additions; quotaTracker
+// withholds them while the account has recently hit its hoster traffic
+// allowance too often to be worth retrying immediately.
+func New(provider debrid.Provider, cfg *config.Config, diskGuard *diskspace.Guard, quotaTracker *quota.Tracker) *Service {
 	return &Service{
-		rd:     rd,
-		config: cfg,
-		logger: logger.New("repair"),
+		provider:  provider,
+		config:    cfg,
+		diskGuard: diskGuard,
+		quota:     quotaTracker,
+		logger:    logger.New("repair"),
 	}
 }
 
+// guardReinsert reports why a magnet reinsert should be withheld, or ""
+// if it's fine to proceed.
+func (s *Service) guardReinsert() string {
+	if s.diskGuard != nil && s.diskGuard.Low() {
+		return "disk space is low"
+	}
+	if s.quota != nil && s.quota.Exceeded() {
+		return "account has recently exceeded its traffic quota"
+	}
+	return ""
+}
+
 // RepairTorrent attempts to repair a dead/failed torrent by reinserting via magnet
-func (s *Service) RepairTorrent(torrent *realdebrid.Torrent, dryRun bool) error {
+func (s *Service) RepairTorrent(torrent *debrid.Torrent, dryRun bool) error {
 	s.logger.Info().
 		Str("id", torrent.ID).
 		Str("filename", torrent.Filename).
@@ -40,24 +63,29 @@ func (s *Service) RepairTorrent(torrent *realdebrid.Torrent, dryRun bool) error
 		return nil
 	}
 
+	if reason := s.guardReinsert(); reason != "" {
+		s.logger.Warn().Str("id", torrent.ID).Str("reason", reason).Msg("Skipping repair reinsert")
+		return fmt.Errorf("skipping repair reinsert: %s", reason)
+	}
+
 	// Step 1: Add magnet
-	newID, err := s.rd.AddMagnet(torrent.Hash)
+	newID, err := s.provider.AddMagnet(torrent.Hash)
 	if err != nil {
 		return fmt.Errorf("adding magnet: %w", err)
 	}
 	s.logger.Debug().Str("newId", newID).Msg("Added magnet for repair")
 
 	// Step 2: Wait for file list and select video files
-	count, err := s.rd.SelectVideoFiles(newID)
+	count, err := s.provider.SelectVideoFiles(newID)
 	if err != nil {
 		// Clean up the new torrent if selection fails
-		s.rd.DeleteTorrent(newID)
+		s.provider.DeleteTorrent(newID)
 		return fmt.Errorf("selecting video files: %w", err)
 	}
 	s.logger.Debug().Int("files", count).Msg("Selected video files")
 
 	// Step 3: Delete the original dead torrent
-	if err := s.rd.DeleteTorrent(torrent.ID); err != nil {
+	if err := s.provider.DeleteTorrent(torrent.ID); err != nil {
 		s.logger.Warn().Err(err).Msg("Failed to delete original torrent")
 		// Don't return error - the repair was successful
 	}
@@ -71,7 +99,7 @@ func (s *Service) RepairTorrent(torrent *realdebrid.Torrent, dryRun bool) error
 }
 
 // RepairTorrents repairs multiple torrents
-func (s *Service) RepairTorrents(torrents []*realdebrid.Torrent, dryRun bool) (int, int) {
+func (s *Service) RepairTorrents(torrents []*debrid.Torrent, dryRun bool) (int, int) {
 	if len(torrents) == 0 {
 		return 0, 0
 	}
@@ -103,16 +131,21 @@ func (s *Service) RepairTorrentByHash(hash string, dryRun bool) error {
 		return nil
 	}
 
+	if reason := s.guardReinsert(); reason != "" {
+		s.logger.Warn().Str("hash", hash[:8]).Str("reason", reason).Msg("Skipping repair reinsert")
+		return fmt.Errorf("skipping repair reinsert: %s", reason)
+	}
+
 	// Add magnet
-	newID, err := s.rd.AddMagnet(hash)
+	newID, err := s.provider.AddMagnet(hash)
 	if err != nil {
 		return fmt.Errorf("adding magnet: %w", err)
 	}
 
 	// Select video files
-	count, err := s.rd.SelectVideoFiles(newID)
+	count, err := s.provider.SelectVideoFiles(newID)
 	if err != nil {
-		s.rd.DeleteTorrent(newID)
+		s.provider.DeleteTorrent(newID)
 		return fmt.Errorf("selecting video files: %w", err)
 	}
 
@@ -2,7 +2,9 @@ package retry
 
 import (
 	"encoding/json"
+	"math/rand"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -10,45 +12,113 @@ import (
 	"github.com/rs/zerolog"
 )
 
-// retry.go persists and manages link retries across sync cycles.
+// retry.go persists and manages link retries across sync cycles, scheduling
+// each retry with an error-type-aware exponential backoff instead of
+// replaying the whole queue every cycle.
+
+// maxRetryDelay caps how long a single item can be deferred, so a
+// persistently misbehaving link still gets retried occasionally.
+const maxRetryDelay = 30 * time.Minute
+
+// baseRetryDelay is the starting backoff per error type before the
+// exponential factor and jitter are applied.
+var baseRetryDelay = map[string]time.Duration{
+	"429": 30 * time.Second,
+	"503": 60 * time.Second,
+}
+
+const defaultBaseRetryDelay = 10 * time.Second
 
 // RetryItem represents a link that failed and needs retry
 type RetryItem struct {
-	Link       string    `json:"link"`
-	TorrentID  string    `json:"torrent_id"`
-	Filename   string    `json:"filename"`
-	AddedAt    time.Time `json:"added_at"`
-	RetryCount int       `json:"retry_count"`
-	LastError  string    `json:"last_error"`
-	ErrorType  string    `json:"error_type"` // "503", "429", "other"
+	Link        string    `json:"link"`
+	TorrentID   string    `json:"torrent_id"`
+	Filename    string    `json:"filename"`
+	// Provider is the debrid backend this link belongs to, so a retry is
+	// replayed against the account that produced it rather than whichever
+	// one happens to be configured first.
+	Provider    string    `json:"provider,omitempty"`
+	AddedAt     time.Time `json:"added_at"`
+	RetryCount  int       `json:"retry_count"`
+	LastError   string    `json:"last_error"`
+	ErrorType   string    `json:"error_type"` // "503", "429", "other"
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// DeadLetterItem is a RetryItem that exceeded MaxAttempts, kept around for
+// operator inspection instead of being discarded silently.
+type DeadLetterItem struct {
+	RetryItem
+	FailedAt time.Time `json:"failed_at"`
+	Reason   string    `json:"reason"`
 }
 
 // Queue manages the retry queue with persistence
 type Queue struct {
-	queueFile string
-	items     []*RetryItem
-	mu        sync.Mutex
-	logger    zerolog.Logger
+	queueFile      string
+	deadLetterFile string
+	maxAttempts    int
+	items          []*RetryItem
+	dead           []*DeadLetterItem
+	mu             sync.Mutex
+	logger         zerolog.Logger
 }
 
-// New creates a new retry queue
-func New(queueFile string) *Queue {
+// New creates a new retry queue. deadLetterFile may be empty, in which case
+// items exceeding maxAttempts are dropped instead of archived. maxAttempts
+// <= 0 disables the dead-letter check.
+func New(queueFile, deadLetterFile string, maxAttempts int) *Queue {
 	q := &Queue{
-		queueFile: queueFile,
-		items:     make([]*RetryItem, 0),
-		logger:    logger.New("retry"),
+		queueFile:      queueFile,
+		deadLetterFile: deadLetterFile,
+		maxAttempts:    maxAttempts,
+		items:          make([]*RetryItem, 0),
+		dead:           make([]*DeadLetterItem, 0),
+		logger:         logger.New("retry"),
 	}
 
 	// Load existing queue
 	if err := q.Load(); err != nil {
 		q.logger.Debug().Err(err).Msg("No existing retry queue, starting fresh")
 	}
+	if err := q.loadDeadLetter(); err != nil {
+		q.logger.Debug().Err(err).Msg("No existing dead-letter queue, starting fresh")
+	}
 
 	return q
 }
 
-// Add adds a link to the retry queue
-func (q *Queue) Add(link, torrentID, filename, errorType, errorMsg string) {
+// nextAttemptFor computes when an item at retryCount should next be
+// attempted: base * 2^retryCount, jittered by up to ±25% and capped at
+// maxRetryDelay.
+func nextAttemptFor(errorType string, retryCount int) time.Time {
+	base, ok := baseRetryDelay[errorType]
+	if !ok {
+		base = defaultBaseRetryDelay
+	}
+
+	if retryCount > 20 {
+		// 2^20 already dwarfs maxRetryDelay; avoid an absurd left shift.
+		retryCount = 20
+	}
+
+	delay := base * time.Duration(int64(1)<<uint(retryCount))
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	jitter := time.Duration((rand.Float64()*0.5 - 0.25) * float64(delay))
+	delay += jitter
+	if delay < 0 {
+		delay = 0
+	}
+
+	return time.Now().Add(delay)
+}
+
+// Add adds a link to the retry queue, tagged with the debrid provider it
+// belongs to so it can be retried against the right account.
+func (q *Queue) Add(link, torrentID, filename, provider, errorType, errorMsg string) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
@@ -58,9 +128,11 @@ func (q *Queue) Add(link, torrentID, filename, errorType, errorMsg string) {
 			// Already in queue, increment retry count
 			item.RetryCount++
 			item.LastError = errorMsg
+			item.NextAttempt = nextAttemptFor(item.ErrorType, item.RetryCount)
 			q.logger.Debug().
 				Str("link", link).
 				Int("retryCount", item.RetryCount).
+				Time("nextAttempt", item.NextAttempt).
 				Msg("Updated existing retry item")
 			return
 		}
@@ -71,17 +143,20 @@ func (q *Queue) Add(link, torrentID, filename, errorType, errorMsg string) {
 		Link:       link,
 		TorrentID:  torrentID,
 		Filename:   filename,
+		Provider:   provider,
 		AddedAt:    time.Now(),
 		RetryCount: 0,
 		LastError:  errorMsg,
 		ErrorType:  errorType,
 	}
+	item.NextAttempt = nextAttemptFor(item.ErrorType, item.RetryCount)
 
 	q.items = append(q.items, item)
 	q.logger.Info().
 		Str("link", link).
 		Str("filename", filename).
 		Str("errorType", errorType).
+		Time("nextAttempt", item.NextAttempt).
 		Msg("Added to retry queue")
 }
 
@@ -96,6 +171,36 @@ func (q *Queue) GetAll() []*RetryItem {
 	return result
 }
 
+// Ready returns the items whose NextAttempt has elapsed, so callers can
+// retry only what's due instead of replaying the whole queue every cycle.
+func (q *Queue) Ready() []*RetryItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	result := make([]*RetryItem, 0, len(q.items))
+	for _, item := range q.items {
+		if !item.NextAttempt.After(now) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// MarkAllReady resets every item's NextAttempt to now, so the next cycle's
+// Ready() picks up the whole queue regardless of backoff. Used by the
+// operator "flush retry queue" command to force an immediate retry pass.
+func (q *Queue) MarkAllReady() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	for _, item := range q.items {
+		item.NextAttempt = now
+	}
+	return len(q.items)
+}
+
 // Remove removes a link from the queue
 func (q *Queue) Remove(link string) {
 	q.mu.Lock()
@@ -112,7 +217,8 @@ func (q *Queue) Remove(link string) {
 	}
 }
 
-// IncrementRetry increments the retry count for a link
+// IncrementRetry increments the retry count for a link and reschedules its
+// next attempt using the backoff for its error type.
 func (q *Queue) IncrementRetry(link string) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -120,16 +226,94 @@ func (q *Queue) IncrementRetry(link string) {
 	for _, item := range q.items {
 		if item.Link == link {
 			item.RetryCount++
+			item.NextAttempt = nextAttemptFor(item.ErrorType, item.RetryCount)
 			q.logger.Debug().
 				Str("link", link).
 				Int("retryCount", item.RetryCount).
+				Time("nextAttempt", item.NextAttempt).
 				Msg("Incremented retry count")
 			return
 		}
 	}
 }
 
-// Save persists the queue to disk
+// MaxedOut reports whether link has exceeded the configured MaxAttempts.
+// maxAttempts <= 0 means no limit is enforced.
+func (q *Queue) MaxedOut(link string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.maxAttempts <= 0 {
+		return false
+	}
+	for _, item := range q.items {
+		if item.Link == link {
+			return item.RetryCount >= q.maxAttempts
+		}
+	}
+	return false
+}
+
+// Fail moves link from the live queue into the dead-letter file, recording
+// reason, so operators can inspect permanent failures without them
+// cluttering the live retry queue.
+func (q *Queue) Fail(link, reason string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, item := range q.items {
+		if item.Link == link {
+			q.dead = append(q.dead, &DeadLetterItem{
+				RetryItem: *item,
+				FailedAt:  time.Now(),
+				Reason:    reason,
+			})
+
+			q.items[i] = q.items[len(q.items)-1]
+			q.items = q.items[:len(q.items)-1]
+
+			q.logger.Warn().
+				Str("link", link).
+				Str("reason", reason).
+				Msg("Moved to dead-letter queue")
+
+			if err := q.saveDeadLetterLocked(); err != nil {
+				q.logger.Warn().Err(err).Msg("Failed to save dead-letter queue")
+			}
+			return
+		}
+	}
+}
+
+// DeadLetter returns a snapshot of every item moved to the dead-letter
+// queue via Fail.
+func (q *Queue) DeadLetter() []*DeadLetterItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result := make([]*DeadLetterItem, len(q.dead))
+	copy(result, q.dead)
+	return result
+}
+
+// ClearDeadLetter discards every dead-letter item and persists the now-empty
+// archive, so operators can acknowledge permanent failures they've reviewed.
+func (q *Queue) ClearDeadLetter() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cleared := len(q.dead)
+	q.dead = make([]*DeadLetterItem, 0)
+	if err := q.saveDeadLetterLocked(); err != nil {
+		return err
+	}
+
+	q.logger.Info().Int("count", cleared).Msg("Cleared dead-letter queue")
+	return nil
+}
+
+// Save persists the queue to disk, atomically, so a crash or a shutdown
+// signal arriving mid-write can't leave a truncated queue file behind.
 func (q *Queue) Save() error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -139,7 +323,7 @@ func (q *Queue) Save() error {
 		return err
 	}
 
-	if err := os.WriteFile(q.queueFile, data, 0644); err != nil {
+	if err := writeFileAtomic(q.queueFile, data); err != nil {
 		return err
 	}
 
@@ -147,6 +331,28 @@ func (q *Queue) Save() error {
 	return nil
 }
 
+// writeFileAtomic writes data to a temp file alongside path and renames it
+// into place, so readers never observe a partially-written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
 // Load reads the queue from disk
 func (q *Queue) Load() error {
 	data, err := os.ReadFile(q.queueFile)
@@ -165,6 +371,43 @@ func (q *Queue) Load() error {
 	return nil
 }
 
+// loadDeadLetter reads the dead-letter archive from disk, if configured.
+func (q *Queue) loadDeadLetter() error {
+	if q.deadLetterFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(q.deadLetterFile)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := json.Unmarshal(data, &q.dead); err != nil {
+		return err
+	}
+
+	q.logger.Debug().Int("count", len(q.dead)).Msg("Loaded dead-letter queue")
+	return nil
+}
+
+// saveDeadLetterLocked persists the dead-letter archive; callers must hold
+// q.mu.
+func (q *Queue) saveDeadLetterLocked() error {
+	if q.deadLetterFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(q.dead, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(q.deadLetterFile, data)
+}
+
 // Count returns the number of items in the queue
 func (q *Queue) Count() int {
 	q.mu.Lock()
@@ -0,0 +1,131 @@
+package retry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/logger"
+)
+
+// retry_test.go guards the backoff curve and the MaxAttempts -> Fail ->
+// dead-letter transition.
+
+// TestMain points the package logger at a scratch directory before any
+// test runs New(), which would otherwise default GetLogPath() to the
+// repo-relative ./logs/robofuse.log and leave a log file behind.
+func TestMain(m *testing.M) {
+	logger.SetLogPath(os.TempDir())
+	os.Exit(m.Run())
+}
+
+func TestNextAttemptFor_GrowsExponentiallyWithinJitterBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		errorType  string
+		retryCount int
+		base       time.Duration
+	}{
+		{"503 base", "503", 0, 60 * time.Second},
+		{"503 doubled once", "503", 1, 120 * time.Second},
+		{"503 doubled thrice", "503", 3, 480 * time.Second},
+		{"429 base", "429", 0, 30 * time.Second},
+		{"429 doubled twice", "429", 2, 120 * time.Second},
+		{"unknown error type uses default base", "timeout", 0, defaultBaseRetryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now()
+			next := nextAttemptFor(tt.errorType, tt.retryCount)
+			delay := next.Sub(before)
+
+			minDelay := time.Duration(float64(tt.base) * 0.75)
+			maxDelay := time.Duration(float64(tt.base) * 1.25)
+			if delay < minDelay || delay > maxDelay {
+				t.Fatalf("delay %v outside jittered range [%v, %v] for base %v", delay, minDelay, maxDelay, tt.base)
+			}
+		})
+	}
+}
+
+func TestNextAttemptFor_ClampsAtMaxRetryDelay(t *testing.T) {
+	// Both a huge retryCount and the documented >20 clamp should land the
+	// same: at maxRetryDelay, jittered.
+	for _, retryCount := range []int{21, 50, 1000} {
+		before := time.Now()
+		next := nextAttemptFor("503", retryCount)
+		delay := next.Sub(before)
+
+		maxJittered := time.Duration(float64(maxRetryDelay) * 1.25)
+		if delay > maxJittered {
+			t.Fatalf("retryCount=%d: delay %v exceeds jittered cap %v", retryCount, delay, maxJittered)
+		}
+		if delay < 0 {
+			t.Fatalf("retryCount=%d: delay %v must not be negative", retryCount, delay)
+		}
+	}
+}
+
+func newTestQueue(t *testing.T) *Queue {
+	t.Helper()
+	dir := t.TempDir()
+	return New(filepath.Join(dir, "retry_queue.json"), filepath.Join(dir, "dead_letter.json"), 3)
+}
+
+func TestQueue_MaxedOut(t *testing.T) {
+	q := newTestQueue(t)
+	q.Add("https://example/link", "torrent1", "file1", "real-debrid", "503", "server error")
+
+	for i := 0; i < 3; i++ {
+		if q.MaxedOut("https://example/link") {
+			t.Fatalf("expected not maxed out after %d retries", i)
+		}
+		q.IncrementRetry("https://example/link")
+	}
+
+	if !q.MaxedOut("https://example/link") {
+		t.Fatalf("expected item to be maxed out after reaching maxAttempts")
+	}
+}
+
+func TestQueue_MaxedOut_DisabledWhenMaxAttemptsIsZero(t *testing.T) {
+	dir := t.TempDir()
+	q := New(filepath.Join(dir, "retry_queue.json"), "", 0)
+	q.Add("https://example/link", "torrent1", "file1", "real-debrid", "503", "server error")
+
+	for i := 0; i < 100; i++ {
+		q.IncrementRetry("https://example/link")
+	}
+
+	if q.MaxedOut("https://example/link") {
+		t.Fatalf("expected MaxedOut to always report false when maxAttempts <= 0")
+	}
+}
+
+func TestQueue_Fail_MovesItemToDeadLetterExactlyOnce(t *testing.T) {
+	q := newTestQueue(t)
+	q.Add("https://example/link", "torrent1", "file1", "real-debrid", "503", "server error")
+
+	q.Fail("https://example/link", "exceeded max attempts")
+
+	if q.Count() != 0 {
+		t.Fatalf("expected item removed from the live queue, got count=%d", q.Count())
+	}
+
+	dead := q.DeadLetter()
+	if len(dead) != 1 {
+		t.Fatalf("expected exactly 1 dead-letter item, got %d", len(dead))
+	}
+	if dead[0].Link != "https://example/link" || dead[0].Reason != "exceeded max attempts" {
+		t.Fatalf("unexpected dead-letter item: %+v", dead[0])
+	}
+
+	// Failing again (e.g. a duplicate call) must not duplicate the entry -
+	// the item is already gone from the live queue, so Fail is a no-op.
+	q.Fail("https://example/link", "exceeded max attempts")
+	if len(q.DeadLetter()) != 1 {
+		t.Fatalf("expected Fail to be a no-op once the item has left the live queue, got %d dead-letter items", len(q.DeadLetter()))
+	}
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/robofuse/robofuse/pkg/progress"
+)
+
+// client.go is the thin dialer used by `robofuse status` and other RPC
+// consumers; it mirrors the one-request-per-connection protocol Server
+// implements.
+
+// Call sends a single RPC request over socketPath and decodes its result
+// into out, which may be nil to discard the result.
+func Call(socketPath, method string, params interface{}, out interface{}) error {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("dialing rpc socket: %w", err)
+	}
+	defer conn.Close()
+
+	req := Request{Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = data
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := conn.Write(data); err != nil {
+		return err
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return fmt.Errorf("decoding rpc response: %w", err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("rpc: %s", resp.Error)
+	}
+
+	if out != nil && resp.Result != nil {
+		data, err := json.Marshal(resp.Result)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamProgress opens a dedicated connection and invokes onEvent for every
+// progress event published until the connection is closed or an error occurs.
+func StreamProgress(socketPath string, onEvent func(progress.Event)) error {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("dialing rpc socket: %w", err)
+	}
+	defer conn.Close()
+
+	data, err := json.Marshal(Request{Method: "StreamProgress"})
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := conn.Write(data); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var event progress.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		onEvent(event)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,21 @@
+package rpc
+
+import "encoding/json"
+
+// protocol.go defines the newline-delimited JSON request/response envelope
+// used over the RPC socket: one Request per line in, one Response per line
+// out, except StreamProgress which keeps streaming Events after its
+// initial Request.
+
+// Request is a single RPC call. Params is method-specific and left raw so
+// handlers only decode what they need.
+type Request struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is the reply to a Request. Exactly one of Result/Error is set.
+type Response struct {
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
@@ -0,0 +1,186 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/pkg/sync"
+	"github.com/rs/zerolog"
+)
+
+// server.go exposes GetStatus/TriggerSync/FlushRetryQueue/RefreshExpiring/
+// ListRetryQueue/ListDeadLetterQueue/ClearDeadLetterQueue/PauseSync/
+// ResumeSync/StreamProgress over a Unix domain socket, so a sync cycle can
+// be observed and steered without tailing logs. Every connection sends one
+// Request and gets one Response, except StreamProgress which keeps the
+// connection open and streams progress.Event values instead.
+
+// Server is the RPC endpoint backed by a sync.Service.
+type Server struct {
+	socketPath string
+	service    *sync.Service
+	logger     zerolog.Logger
+}
+
+// NewServer creates a Server that will listen on socketPath once started.
+func NewServer(socketPath string, service *sync.Service) *Server {
+	return &Server{
+		socketPath: socketPath,
+		service:    service,
+		logger:     logger.New("rpc"),
+	}
+}
+
+// ListenAndServe accepts connections until ctx is cancelled, cleaning up
+// the socket file on every exit path, including errors.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	// Remove a stale socket left behind by an unclean shutdown.
+	_ = os.Remove(s.socketPath)
+
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on rpc socket: %w", err)
+	}
+	defer os.Remove(s.socketPath)
+	defer listener.Close()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	s.logger.Info().Str("socket", s.socketPath).Msg("RPC socket listening")
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			s.logger.Warn().Err(err).Msg("Accept failed")
+			continue
+		}
+
+		go s.handleConn(ctx, conn)
+	}
+}
+
+func (s *Server) handleConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return
+	}
+
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		writeResponse(conn, Response{Error: "invalid request: " + err.Error()})
+		return
+	}
+
+	if req.Method == "StreamProgress" {
+		s.streamProgress(ctx, conn)
+		return
+	}
+
+	result, err := s.dispatch(req)
+	if err != nil {
+		writeResponse(conn, Response{Error: err.Error()})
+		return
+	}
+	writeResponse(conn, Response{Result: result})
+}
+
+func (s *Server) dispatch(req Request) (interface{}, error) {
+	switch req.Method {
+	case "GetStatus":
+		return s.service.Status(), nil
+
+	case "TriggerSync":
+		var params struct {
+			DryRun bool `json:"dry_run"`
+		}
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, fmt.Errorf("decoding params: %w", err)
+			}
+		}
+		go func() {
+			if _, err := s.service.TriggerSync(context.Background(), params.DryRun); err != nil {
+				s.logger.Warn().Err(err).Msg("Triggered sync failed")
+			}
+		}()
+		return map[string]string{"status": "triggered"}, nil
+
+	case "FlushRetryQueue":
+		return map[string]int{"count": s.service.FlushRetryQueue()}, nil
+
+	case "RefreshExpiring":
+		go func() {
+			if err := s.service.RefreshExpiringLinks(context.Background()); err != nil {
+				s.logger.Warn().Err(err).Msg("Triggered expiring-link refresh failed")
+			}
+		}()
+		return map[string]string{"status": "triggered"}, nil
+
+	case "ListRetryQueue":
+		return s.service.RetryQueueItems(), nil
+
+	case "ListDeadLetterQueue":
+		return s.service.DeadLetterQueueItems(), nil
+
+	case "ClearDeadLetterQueue":
+		if err := s.service.ClearDeadLetterQueue(); err != nil {
+			return nil, err
+		}
+		return map[string]string{"status": "cleared"}, nil
+
+	case "PauseSync":
+		s.service.Pause()
+		return map[string]string{"status": "paused"}, nil
+
+	case "ResumeSync":
+		s.service.Resume()
+		return map[string]string{"status": "resumed"}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown method %q", req.Method)
+	}
+}
+
+func (s *Server) streamProgress(ctx context.Context, conn net.Conn) {
+	ch, cancel := s.service.Progress().Subscribe()
+	defer cancel()
+
+	enc := json.NewEncoder(conn)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := enc.Encode(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeResponse(conn net.Conn, resp Response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+	conn.Write(data)
+}
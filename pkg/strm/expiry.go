@@ -1,8 +1,6 @@
 package strm
 
 import (
-	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/robofuse/robofuse/pkg/tracking"
@@ -13,22 +11,35 @@ func (s *Service) GetExpiredFiles(olderThan time.Duration) []*tracking.FileTrack
 	return s.tracking.GetExpired(olderThan)
 }
 
-// UpdateSTRM updates an existing STRM file with a new URL and refreshes tracking
-func (s *Service) UpdateSTRM(relativePath, newURL, link, torrentID string) error {
-	fullPath := filepath.Join(s.config.OutputDir, relativePath)
+// TrackingSnapshot returns a copy of all tracked files, for debug/inspection endpoints.
+func (s *Service) TrackingSnapshot() map[string]*tracking.FileTracking {
+	return s.tracking.All()
+}
+
+// Tracking returns the live tracking store backing this Service, for
+// callers like pkg/mount that need to resolve entries as Sync updates them
+// rather than working from a point-in-time snapshot.
+func (s *Service) Tracking() *tracking.Service {
+	return s.tracking
+}
 
-	// Write new URL to STRM file
-	if err := os.WriteFile(fullPath, []byte(newURL), 0644); err != nil {
+// UpdateSTRM updates an existing STRM file with a new URL and refreshes
+// tracking. provider is stamped onto the refreshed entry so it keeps
+// pointing at whichever debrid account originally produced link.
+func (s *Service) UpdateSTRM(relativePath, newURL, link, torrentID, provider string) error {
+	var fallbacks []string
+	if prev, ok := s.tracking.Get(relativePath); ok && prev.DownloadURL != newURL {
+		fallbacks = []string{prev.DownloadURL}
+	}
+
+	// Write new URL/content through the configured backend (or re-fetch the
+	// real content in cache mode)
+	if err := s.writeOutput(relativePath, newURL, fallbacks); err != nil {
 		return err
 	}
 
 	// Update tracking with new URL and refresh timestamp
-	s.tracking.Track(relativePath, newURL, link, torrentID)
-
-	// Save tracking data
-	if err := s.tracking.Save(); err != nil {
-		s.logger.Warn().Err(err).Msg("Failed to save tracking  after update")
-	}
+	s.tracking.Track(relativePath, newURL, link, torrentID, s.backend(), provider)
 
 	s.logger.Debug().Str("path", relativePath).Msg("Refreshed STRM file")
 	return nil
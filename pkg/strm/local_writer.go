@@ -0,0 +1,47 @@
+package strm
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// local_writer.go is the default Writer: plain files under OutputDir.
+
+// localWriter writes files to a directory on the local filesystem.
+type localWriter struct {
+	root string
+}
+
+func newLocalWriter(root string) *localWriter {
+	return &localWriter{root: root}
+}
+
+func (w *localWriter) Write(path string, data []byte) error {
+	fullPath := filepath.Join(w.root, path)
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(fullPath, data, 0644)
+}
+
+func (w *localWriter) Remove(path string) error {
+	err := os.Remove(filepath.Join(w.root, path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (w *localWriter) Stat(path string) (FileInfo, error) {
+	info, err := os.Stat(filepath.Join(w.root, path))
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return FileInfo{Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (w *localWriter) Backend() string {
+	return "local"
+}
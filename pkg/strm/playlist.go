@@ -0,0 +1,54 @@
+package strm
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// playlist.go renders the on-disk content of a STRM output file: a plain
+// URL for the default "strm" format, or an M3U8 playlist carrying fallback
+// URLs for "m3u" (config.OutputFormat), so a player can fail over instead
+// of dead-ending when the primary link 404s.
+
+// renderOutput builds the bytes writeOutput writes for relativePath. url is
+// the primary (remapped) URL; fallbacks are additional URLs to offer, in
+// order of preference, and are ignored entirely in the "strm" format.
+func (s *Service) renderOutput(relativePath, url string, fallbacks []string) []byte {
+	primary := s.remapURL(url)
+
+	if s.config.OutputFormat != "m3u" {
+		return []byte(primary)
+	}
+
+	title := strings.TrimSuffix(filepath.Base(relativePath), filepath.Ext(relativePath))
+
+	var b strings.Builder
+	fmt.Fprintln(&b, "#EXTM3U")
+	fmt.Fprintf(&b, "#EXTINF:-1,%s\n", title)
+	fmt.Fprintln(&b, primary)
+	for _, fb := range fallbacks {
+		if fb == "" || fb == url {
+			continue
+		}
+		fmt.Fprintf(&b, "#EXTVLCOPT:fallback-url=%s\n", s.remapURL(fb))
+	}
+
+	return []byte(b.String())
+}
+
+// parseOutputContent recovers the primary URL from previously written
+// output content, whichever format produced it: plain "strm" content is
+// just that URL, and M3U8's #EXTM3U/#EXTINF/#EXTVLCOPT lines are all
+// comments, so the first non-empty, non-comment line is the primary URL
+// either way.
+func parseOutputContent(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return line
+	}
+	return strings.TrimSpace(content)
+}
@@ -0,0 +1,92 @@
+package strm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/config"
+)
+
+// rclone_writer.go shells out to the rclone binary, so any of its dozens of
+// remote types (S3, Google Drive, a second robofuse host, ...) can serve as
+// a STRM output target without robofuse linking against each one directly.
+type rcloneWriter struct {
+	remote string
+	binary string
+}
+
+func newRcloneWriter(cfg config.RcloneConfig) *rcloneWriter {
+	binary := cfg.Binary
+	if binary == "" {
+		binary = "rclone"
+	}
+	return &rcloneWriter{remote: strings.TrimRight(cfg.Remote, "/"), binary: binary}
+}
+
+func (w *rcloneWriter) remotePath(p string) string {
+	return w.remote + "/" + path.Clean(p)
+}
+
+func (w *rcloneWriter) Write(p string, data []byte) error {
+	cmd := exec.Command(w.binary, "rcat", w.remotePath(p))
+	cmd.Stdin = bytes.NewReader(data)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("rclone rcat: %w: %s", err, stderr.String())
+	}
+	return nil
+}
+
+func (w *rcloneWriter) Remove(p string) error {
+	cmd := exec.Command(w.binary, "deletefile", w.remotePath(p))
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if strings.Contains(stderr.String(), "not found") {
+			return nil
+		}
+		return fmt.Errorf("rclone deletefile: %w: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// rcloneLsEntry is the subset of `rclone lsjson` output fields we need.
+type rcloneLsEntry struct {
+	Size    int64  `json:"Size"`
+	ModTime string `json:"ModTime"`
+}
+
+func (w *rcloneWriter) Stat(p string) (FileInfo, error) {
+	cmd := exec.Command(w.binary, "lsjson", w.remotePath(p))
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return FileInfo{}, fmt.Errorf("rclone lsjson: %w: %s", err, stderr.String())
+	}
+
+	var entries []rcloneLsEntry
+	if err := json.Unmarshal(stdout.Bytes(), &entries); err != nil {
+		return FileInfo{}, err
+	}
+	if len(entries) == 0 {
+		return FileInfo{}, os.ErrNotExist
+	}
+
+	modTime, _ := time.Parse(time.RFC3339Nano, entries[0].ModTime)
+	return FileInfo{Size: entries[0].Size, ModTime: modTime}, nil
+}
+
+func (w *rcloneWriter) Backend() string {
+	return "rclone"
+}
@@ -0,0 +1,118 @@
+package strm
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/robofuse/robofuse/internal/config"
+)
+
+// remap.go applies user-configured find/replace rules to STRM output paths
+// and URLs, so a library can be migrated between hosts (e.g. a Windows
+// mount to a Linux container) without regenerating it.
+
+// pathRemapRule is a compiled config.PathRemapRule. re is non-nil when the
+// rule is a regex; otherwise from/to are matched/replaced literally.
+type pathRemapRule struct {
+	from string
+	to   string
+	re   *regexp.Regexp
+}
+
+// compilePathRemapRules compiles cfg's rules once at startup rather than
+// per-candidate, since Sync can apply them thousands of times per run.
+func compilePathRemapRules(cfg config.PathRemapConfig) ([]pathRemapRule, error) {
+	rules := make([]pathRemapRule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		rule := pathRemapRule{from: r.From, to: r.To}
+		if r.Regex {
+			re, err := regexp.Compile(r.From)
+			if err != nil {
+				return nil, fmt.Errorf("compiling path remap rule %q: %w", r.From, err)
+			}
+			rule.re = re
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// remap applies rules to s in order.
+func remap(rules []pathRemapRule, s string) string {
+	for _, r := range rules {
+		if r.re != nil {
+			s = r.re.ReplaceAllString(s, r.to)
+		} else {
+			s = strings.ReplaceAll(s, r.from, r.to)
+		}
+	}
+	return s
+}
+
+// unmap applies rules to s in reverse order with from/to swapped, to
+// recover the pre-remap form of an already-remapped path or URL. Regex
+// rules aren't generally invertible, so they're skipped here; a sync run
+// right after changing only literal rules still matches up against
+// previously written files instead of treating all of them as orphans.
+func unmap(rules []pathRemapRule, s string) string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		r := rules[i]
+		if r.re != nil {
+			continue
+		}
+		s = strings.ReplaceAll(s, r.to, r.from)
+	}
+	return s
+}
+
+// remapPath applies the service's path rules to one path component (a
+// sanitized folder or file name, per config.PathRemapConfig's doc comment:
+// after sanitizeFilename, before filepath.Join).
+func (s *Service) remapPath(component string) string {
+	return remap(s.pathRemapRules, component)
+}
+
+// unmapPath reverses remapPath on one path component. unmapSTRMPath calls
+// this on a relative path's folder and file components, the same way
+// buildSTRMPath calls remapPath on them going forward.
+func (s *Service) unmapPath(component string) string {
+	return unmap(s.pathRemapRules, component)
+}
+
+// unmapSTRMPath reverses buildSTRMPath's remap/PathSeparator transforms on
+// a relative path read back from disk or tracking, recovering the
+// canonical (pre-remap) key canonicalSTRMPath computes for the same
+// candidate - so scanExisting still matches it up after a path remap rule
+// edit, as long as the edit doesn't actually touch this file's components.
+func (s *Service) unmapSTRMPath(path string) string {
+	if s.config.PathRemap.PathSeparator != "" {
+		path = strings.ReplaceAll(path, s.config.PathRemap.PathSeparator, "/")
+	}
+	path = filepath.ToSlash(path)
+	dir, name := filepath.Split(path)
+	return filepath.Join(s.unmapPath(strings.TrimSuffix(dir, "/")), s.unmapPath(name))
+}
+
+// remapURL applies the service's path rules to a download URL before it's
+// written into a .strm file.
+func (s *Service) remapURL(url string) string {
+	return remap(s.pathRemapRules, url)
+}
+
+// unmapURL reverses remapURL, for comparing a URL read back from an
+// existing .strm file against the canonical (pre-remap) candidate URL.
+func (s *Service) unmapURL(url string) string {
+	return unmap(s.pathRemapRules, url)
+}
+
+// applyPathSeparator rewrites path's separators to the configured override,
+// if any, after rules have already been applied and the path joined.
+func (s *Service) applyPathSeparator(path string) string {
+	if s.config.PathRemap.PathSeparator == "" {
+		return path
+	}
+	path = strings.ReplaceAll(path, "\\", "/")
+	return strings.ReplaceAll(path, "/", s.config.PathRemap.PathSeparator)
+}
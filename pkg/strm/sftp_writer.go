@@ -0,0 +1,129 @@
+package strm
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/pkg/sftp"
+	"github.com/robofuse/robofuse/internal/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftp_writer.go writes STRM files to a remote host over SFTP, connecting
+// lazily on first use and reusing the session across calls.
+type sftpWriter struct {
+	cfg config.SFTPConfig
+
+	mu     sync.Mutex
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+func newSFTPWriter(cfg config.SFTPConfig) *sftpWriter {
+	return &sftpWriter{cfg: cfg}
+}
+
+func (w *sftpWriter) connect() (*sftp.Client, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.client != nil {
+		return w.client, nil
+	}
+
+	var auth []ssh.AuthMethod
+	if w.cfg.PrivateKeyPath != "" {
+		key, err := os.ReadFile(w.cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading private key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("parsing private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if w.cfg.Password != "" {
+		auth = append(auth, ssh.Password(w.cfg.Password))
+	}
+
+	port := w.cfg.Port
+	if port == 0 {
+		port = 22
+	}
+
+	conn, err := ssh.Dial("tcp", fmt.Sprintf("%s:%d", w.cfg.Host, port), &ssh.ClientConfig{
+		User:            w.cfg.Username,
+		Auth:            auth,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dialing sftp host: %w", err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("starting sftp session: %w", err)
+	}
+
+	w.conn = conn
+	w.client = client
+	return client, nil
+}
+
+func (w *sftpWriter) fullPath(p string) string {
+	return path.Join(w.cfg.BaseDir, p)
+}
+
+func (w *sftpWriter) Write(p string, data []byte) error {
+	client, err := w.connect()
+	if err != nil {
+		return err
+	}
+
+	full := w.fullPath(p)
+	if err := client.MkdirAll(path.Dir(full)); err != nil {
+		return fmt.Errorf("sftp mkdir: %w", err)
+	}
+
+	f, err := client.Create(full)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return err
+}
+
+func (w *sftpWriter) Remove(p string) error {
+	client, err := w.connect()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Remove(w.fullPath(p)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (w *sftpWriter) Stat(p string) (FileInfo, error) {
+	client, err := w.connect()
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	info, err := client.Stat(w.fullPath(p))
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return FileInfo{Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (w *sftpWriter) Backend() string {
+	return "sftp"
+}
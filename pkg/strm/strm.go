@@ -1,14 +1,22 @@
 package strm
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/robofuse/robofuse/internal/config"
+	"github.com/robofuse/robofuse/internal/console"
+	"github.com/robofuse/robofuse/internal/diskspace"
 	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/internal/request"
+	"github.com/robofuse/robofuse/internal/store"
+	"github.com/robofuse/robofuse/pkg/fetch"
+	"github.com/robofuse/robofuse/pkg/progress"
 	"github.com/robofuse/robofuse/pkg/realdebrid"
 	"github.com/robofuse/robofuse/pkg/tracking"
 	"github.com/rs/zerolog"
@@ -18,18 +26,70 @@ import (
 
 // Service handles STRM file generation
 type Service struct {
-	config   *config.Config
-	logger   zerolog.Logger
-	tracking *tracking.Service
+	config         *config.Config
+	logger         zerolog.Logger
+	tracking       *tracking.Service
+	writer         Writer
+	diskGuard      *diskspace.Guard
+	fetcher        *fetch.Fetcher  // non-nil when config.CacheMode is enabled
+	pathRemapRules []pathRemapRule // config.PathRemap.Rules, compiled once
 }
 
-// New creates a new STRM service
-func New(cfg *config.Config) *Service {
-	return &Service{
+// New creates a new STRM service. downloadsClient is the rate-limited HTTP
+// client cache mode uses to fetch real file content; it may be nil when
+// cache mode is disabled. db is the shared store the organizer also reads
+// tracking data from.
+func New(cfg *config.Config, downloadsClient *request.Client, hub *progress.Hub, db *store.DB) *Service {
+	s := &Service{
 		config:   cfg,
 		logger:   logger.New("strm"),
-		tracking: tracking.New(cfg.TrackingFile),
+		tracking: tracking.New(db, cfg.TrackingFile),
+		writer:   NewWriter(cfg),
+		diskGuard: diskspace.NewGuard(diskspace.GuardConfig{
+			Paths:           cfg.DiskSpaceGuardPaths(),
+			MinFreeBytes:    cfg.DiskSpaceMinFreeBytes,
+			MinFreePercent:  cfg.DiskSpaceMinFreePercent,
+			CheckInterval:   time.Duration(cfg.DiskSpaceCheckIntervalSec) * time.Second,
+			BackoffOnLow:    time.Duration(cfg.DiskSpaceBackoffSec) * time.Second,
+			MaxBackoffOnLow: time.Duration(cfg.DiskSpaceMaxBackoffSec) * time.Second,
+		}),
 	}
+
+	if cfg.CacheMode {
+		chunkSize := int64(cfg.Fetch.ChunkSizeMB) * 1024 * 1024
+		s.fetcher = fetch.New(downloadsClient, cfg.Fetch.MaxChunks, chunkSize, hub)
+	}
+
+	rules, err := compilePathRemapRules(cfg.PathRemap)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Ignoring invalid path remap config")
+	} else {
+		s.pathRemapRules = rules
+	}
+
+	return s
+}
+
+// backend returns the name tracking data should be stamped with for files
+// this service writes: "cache" when cache mode materializes real content
+// locally, otherwise whatever output backend is configured.
+func (s *Service) backend() string {
+	if s.config.CacheMode {
+		return "cache"
+	}
+	return s.writer.Backend()
+}
+
+// candidateProvider returns the name tracking data should be stamped with
+// for which debrid account produced c. Candidates built before this field
+// existed (or from a single-account config) leave it empty, so those fall
+// back to "real-debrid" - robofuse's only provider until aggregation was
+// added.
+func candidateProvider(c realdebrid.STRMCandidate) string {
+	if c.Provider != "" {
+		return c.Provider
+	}
+	return "real-debrid"
 }
 
 // SyncResult contains the results of a sync operation
@@ -38,6 +98,10 @@ type SyncResult struct {
 	Updated int
 	Deleted int
 	Skipped int
+	// DiskSkipped counts new files withheld because the disk guard reported
+	// low free space; updates and deletes still proceed since they don't
+	// grow disk usage (deletes actively free it).
+	DiskSkipped int
 	Tracked int
 }
 
@@ -45,59 +109,81 @@ type SyncResult struct {
 func (s *Service) Sync(candidates []realdebrid.STRMCandidate, dryRun bool) (*SyncResult, error) {
 	result := &SyncResult{}
 
-	// Ensure output directory exists
-	if !dryRun {
+	// Ensure output directory exists (local backend only; remote backends
+	// create parent collections/directories as they write).
+	if !dryRun && (s.config.CacheMode || s.writer.Backend() == "local") {
 		if err := os.MkdirAll(s.config.OutputDir, 0755); err != nil {
 			return nil, err
 		}
 	}
 
+	if !dryRun && s.config.CacheMode && logger.IsInfoEnabled() && !logger.IsDebugEnabled() {
+		mgr := console.NewProgressManager()
+		s.fetcher.SetConsole(mgr)
+		defer func() {
+			mgr.Stop()
+			s.fetcher.SetConsole(nil)
+		}()
+	}
+
 	// Step 1: Scan existing STRM files
 	existing, err := s.scanExisting()
 	if err != nil {
 		return nil, err
 	}
 
-	// Step 2: Build expected map from candidates
-	expected := make(map[string]string) // relativePath -> downloadURL
+	// Step 2: Build expected map from candidates, keyed by the canonical
+	// (pre-remap) path rather than buildSTRMPath's on-disk one, so this
+	// matches up against scanExisting's keys the same way even after a
+	// path remap rule edit - see canonicalSTRMPath.
+	expected := make(map[string]string) // canonicalPath -> downloadURL
 	candidateMap := make(map[string]realdebrid.STRMCandidate)
 	for _, c := range candidates {
-		path := s.buildSTRMPath(c.TorrentFolder, c.Filename)
-		expected[path] = c.DownloadURL
-		candidateMap[path] = c
+		key := s.canonicalSTRMPath(c.TorrentFolder, c.Filename)
+		expected[key] = c.DownloadURL
+		candidateMap[key] = c
 	}
 
-	// Step 3: Process candidates (add/update)
-	for path, url := range expected {
-		existingURL, exists := existing[path]
+	// Step 3: Process candidates (add/update). Low disk space only
+	// withholds new additions - updates and deletes still run below, since
+	// neither grows disk usage.
+	diskLow := !dryRun && s.diskGuard.Low()
+
+	for key, url := range expected {
+		existingEnt, exists := existing[key]
+		candidate := candidateMap[key]
+		fallbacks := candidate.FallbackURLs
+		path := s.buildSTRMPath(candidate.TorrentFolder, candidate.Filename)
 
 		if exists {
-			if existingURL == url {
+			if existingEnt.url == url {
 				result.Skipped++
 			} else {
-				// Different URL - update
+				// Different URL - update, offering the previous URL as a
+				// fallback in case the new one turns out to be stale.
 				result.Updated++
 				if !dryRun {
-					if err := s.writeSTRM(path, url); err != nil {
+					if err := s.writeOutput(path, url, append(append([]string{}, fallbacks...), existingEnt.url)); err != nil {
 						s.logger.Error().Err(err).Str("path", path).Msg("Failed to update STRM")
 					} else {
 						// Track the update
-						candidate := candidateMap[path]
-						s.tracking.Track(path, url, candidate.Link, candidate.TorrentID)
+						s.tracking.Track(path, url, candidate.Link, candidate.TorrentID, s.backend(), candidateProvider(candidate))
 					}
 				}
 				s.logger.Debug().Str("path", path).Msg("Updated STRM")
 			}
+		} else if diskLow {
+			result.DiskSkipped++
+			s.logger.Debug().Str("path", path).Msg("Skipped new STRM, disk space low")
 		} else {
 			// New file
 			result.Added++
 			if !dryRun {
-				if err := s.writeSTRM(path, url); err != nil {
+				if err := s.writeOutput(path, url, fallbacks); err != nil {
 					s.logger.Error().Err(err).Str("path", path).Msg("Failed to create STRM")
 				} else {
 					// Track the new file
-					candidate := candidateMap[path]
-					s.tracking.Track(path, url, candidate.Link, candidate.TorrentID)
+					s.tracking.Track(path, url, candidate.Link, candidate.TorrentID, s.backend(), candidateProvider(candidate))
 				}
 			}
 			s.logger.Debug().Str("path", path).Msg("Created STRM")
@@ -105,28 +191,22 @@ func (s *Service) Sync(candidates []realdebrid.STRMCandidate, dryRun bool) (*Syn
 	}
 
 	// Step 4: Delete orphans
-	for path := range existing {
-		if _, exists := expected[path]; !exists {
+	for key, ent := range existing {
+		if _, exists := expected[key]; !exists {
 			result.Deleted++
 			if !dryRun {
-				fullPath := filepath.Join(s.config.OutputDir, path)
-				if err := os.Remove(fullPath); err != nil {
-					s.logger.Error().Err(err).Str("path", path).Msg("Failed to delete STRM")
+				if err := s.writer.Remove(ent.path); err != nil {
+					s.logger.Error().Err(err).Str("path", ent.path).Msg("Failed to delete STRM")
 				} else {
 					// Remove from tracking
-					s.tracking.Remove(path)
+					s.tracking.Remove(ent.path)
+				}
+				// Try to remove empty parent directory (local backend only)
+				if s.config.CacheMode || s.writer.Backend() == "local" {
+					s.cleanupEmptyDirs(filepath.Dir(filepath.Join(s.config.OutputDir, ent.path)))
 				}
-				// Try to remove empty parent directory
-				s.cleanupEmptyDirs(filepath.Dir(fullPath))
 			}
-			s.logger.Debug().Str("path", path).Msg("Deleted orphan STRM")
-		}
-	}
-
-	// Save tracking data
-	if !dryRun {
-		if err := s.tracking.Save(); err != nil {
-			s.logger.Warn().Err(err).Msg("Failed to save tracking data")
+			s.logger.Debug().Str("path", ent.path).Msg("Deleted orphan STRM")
 		}
 	}
 
@@ -137,6 +217,7 @@ func (s *Service) Sync(candidates []realdebrid.STRMCandidate, dryRun bool) (*Syn
 		Int("updated", result.Updated).
 		Int("deleted", result.Deleted).
 		Int("skipped", result.Skipped).
+		Int("diskSkipped", result.DiskSkipped).
 		Int("tracked", result.Tracked).
 		Bool("dryRun", dryRun).
 		Msg("STRM sync completed")
@@ -144,9 +225,30 @@ func (s *Service) Sync(candidates []realdebrid.STRMCandidate, dryRun bool) (*Syn
 	return result, nil
 }
 
-// scanExisting scans the output directory for existing STRM files
-func (s *Service) scanExisting() (map[string]string, error) {
-	existing := make(map[string]string)
+// existingEntry is one already-materialized output file: the URL it
+// currently points at (for comparing against a candidate's DownloadURL)
+// alongside the actual on-disk/tracked relative path (for Stat/Write/
+// Remove calls, which need the post-remap path rather than the canonical
+// key existing is indexed by).
+type existingEntry struct {
+	url  string
+	path string
+}
+
+// scanExisting returns the set of existing STRM files, keyed by the
+// canonical (pre-remap) path so it lines up with "expected" even after a
+// path remap rule edit, mapped to the URL they currently point at.
+func (s *Service) scanExisting() (map[string]existingEntry, error) {
+	if !s.config.CacheMode && s.writer.Backend() == "local" {
+		return s.scanExistingLocal()
+	}
+	return s.scanExistingFromTracking(), nil
+}
+
+// scanExistingLocal walks the output directory directly, the original
+// behavior and still the cheapest option for a plain local mount.
+func (s *Service) scanExistingLocal() (map[string]existingEntry, error) {
+	existing := make(map[string]existingEntry)
 
 	err := filepath.Walk(s.config.OutputDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -155,7 +257,9 @@ func (s *Service) scanExisting() (map[string]string, error) {
 		if info.IsDir() {
 			return nil
 		}
-		if !strings.HasSuffix(strings.ToLower(path), ".strm") {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".strm", ".m3u8":
+		default:
 			return nil
 		}
 
@@ -171,7 +275,14 @@ func (s *Service) scanExisting() (map[string]string, error) {
 			return nil
 		}
 
-		existing[relPath] = strings.TrimSpace(string(content))
+		// Reverse any path remap rules (on both the path itself and the
+		// URL embedded in its content) so this matches up against
+		// "expected", even if the rules were tweaked - but otherwise had
+		// no effect on this particular file - since it was written.
+		existing[s.unmapSTRMPath(relPath)] = existingEntry{
+			url:  s.unmapURL(parseOutputContent(string(content))),
+			path: relPath,
+		}
 		return nil
 	})
 
@@ -182,28 +293,82 @@ func (s *Service) scanExisting() (map[string]string, error) {
 	return existing, nil
 }
 
-// buildSTRMPath builds the relative path for a STRM file
-func (s *Service) buildSTRMPath(folderName, filename string) string {
+// scanExistingFromTracking rebuilds "existing" from tracking data instead
+// of listing the remote backend, confirming each entry is still present
+// with Stat. Tracked files from a different backend (e.g. left over from a
+// prior "local" run) are ignored rather than reported as orphans, since
+// this run never wrote them and can't be sure they even live at this path.
+func (s *Service) scanExistingFromTracking() map[string]existingEntry {
+	existing := make(map[string]existingEntry)
+
+	for path, url := range s.tracking.ForBackend(s.backend()) {
+		if _, err := s.writer.Stat(path); err != nil {
+			continue
+		}
+		existing[s.unmapSTRMPath(path)] = existingEntry{url: url, path: path}
+	}
+
+	return existing
+}
+
+// canonicalSTRMPath builds the pre-remap relative path for a candidate: the
+// same sanitizing and output-extension logic buildSTRMPath applies, without
+// the path remap rules or PathSeparator override. This is the key Sync
+// matches candidates against existing output with, so a path remap rule
+// edit that doesn't actually touch a given candidate's folder/file name
+// doesn't make Sync treat it as a brand new file - buildSTRMPath re-applies
+// the current rules on top of this to get the path actually used for I/O.
+func (s *Service) canonicalSTRMPath(folderName, filename string) string {
 	folder := sanitizeFilename(folderName)
 	file := sanitizeFilename(filename)
 
-	// Change extension to .strm
-	ext := filepath.Ext(file)
-	strmName := strings.TrimSuffix(file, ext) + ".strm"
+	if s.config.CacheMode {
+		return filepath.Join(folder, file)
+	}
 
-	return filepath.Join(folder, strmName)
+	// Change extension to match the configured output format.
+	outExt := ".strm"
+	if s.config.OutputFormat == "m3u" {
+		outExt = ".m3u8"
+	}
+	ext := filepath.Ext(file)
+	return filepath.Join(folder, strings.TrimSuffix(file, ext)+outExt)
 }
 
-// writeSTRM writes a STRM file with the given URL
-func (s *Service) writeSTRM(relativePath, url string) error {
-	fullPath := filepath.Join(s.config.OutputDir, relativePath)
+// buildSTRMPath builds the relative path for a STRM file. In cache mode the
+// original filename (and extension) is kept, since the file materialized
+// there is the real content rather than a .strm pointer. Path remap rules
+// and the PathSeparator override, if configured, are applied to the folder
+// and file name after sanitizing but before joining them.
+func (s *Service) buildSTRMPath(folderName, filename string) string {
+	folder := s.remapPath(sanitizeFilename(folderName))
+	file := sanitizeFilename(filename)
+
+	if s.config.CacheMode {
+		return s.applyPathSeparator(filepath.Join(folder, s.remapPath(file)))
+	}
 
-	// Ensure parent directory exists
-	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
-		return err
+	// Change extension to match the configured output format.
+	outExt := ".strm"
+	if s.config.OutputFormat == "m3u" {
+		outExt = ".m3u8"
 	}
+	ext := filepath.Ext(file)
+	strmName := s.remapPath(strings.TrimSuffix(file, ext) + outExt)
 
-	return os.WriteFile(fullPath, []byte(url), 0644)
+	return s.applyPathSeparator(filepath.Join(folder, strmName))
+}
+
+// writeOutput materializes one candidate at relativePath: a .strm/.m3u8
+// pointer file through the configured writer backend, or in cache mode the
+// real file content fetched in parallel range requests. fallbacks is
+// ignored outside the "m3u" output format.
+func (s *Service) writeOutput(relativePath, url string, fallbacks []string) error {
+	if s.config.CacheMode {
+		dest := filepath.Join(s.config.OutputDir, relativePath)
+		return s.fetcher.Fetch(context.Background(), url, dest, relativePath)
+	}
+	return s.writer.Write(relativePath, s.renderOutput(relativePath, url, fallbacks))
 }
 
 // cleanupEmptyDirs removes empty directories up to the output root
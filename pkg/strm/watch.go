@@ -0,0 +1,141 @@
+package strm
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/robofuse/robofuse/internal/debounce"
+)
+
+// watch.go is an incremental alternative to Sync: a long-lived fsnotify
+// watch on OutputDir that reacts to a .strm file being deleted or renamed
+// outside of a sync cycle (e.g. by a user or a media scanner), instead of
+// waiting for the next full reconciliation pass.
+
+// watchDebounce coalesces the burst of events a single file operation (or a
+// scraper's batch cleanup) tends to produce into one reconcile per path.
+const watchDebounce = 2 * time.Second
+
+// Watch runs until ctx is cancelled, watching config.OutputDir and
+// reconciling individual files as they're deleted or renamed: a tracked
+// file that disappears is rewritten from its tracked download URL, unless
+// its path matches one of config.AuthoritativeDeletePatterns, in which
+// case the deletion is treated as intentional and the file is untracked
+// instead. It complements, rather than replaces, the periodic full Sync a
+// daemon already runs.
+func (s *Service) Watch(ctx context.Context) error {
+	if s.config.CacheMode || s.writer.Backend() != "local" {
+		return fmt.Errorf("strm: Watch requires the local output backend")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("strm: starting watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := s.addRecursive(watcher, s.config.OutputDir); err != nil {
+		return fmt.Errorf("strm: watching %s: %w", s.config.OutputDir, err)
+	}
+
+	deb := debounce.New(watchDebounce, func(path string) { s.reconcileOutputPath(path) })
+	defer deb.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := s.addRecursive(watcher, event.Name); err != nil {
+						s.logger.Warn().Err(err).Str("dir", event.Name).Msg("Failed to watch new directory")
+					}
+					continue
+				}
+			}
+
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				deb.Trigger(event.Name)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			s.logger.Warn().Err(err).Msg("Watcher error")
+		}
+	}
+}
+
+// addRecursive adds dir and all of its subdirectories to watcher, since
+// fsnotify only watches the directory it's given, not its descendants.
+func (s *Service) addRecursive(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// reconcileOutputPath handles a .strm file that went missing at fullPath:
+// an authoritative delete is accepted by untracking it, anything else is
+// recreated from its tracked download URL.
+func (s *Service) reconcileOutputPath(fullPath string) {
+	if _, err := os.Stat(fullPath); err == nil {
+		// Reappeared (e.g. a rename's destination) before the debounce
+		// fired - nothing to reconcile.
+		return
+	}
+
+	relPath, err := filepath.Rel(s.config.OutputDir, fullPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return
+	}
+
+	entry, tracked := s.tracking.Get(relPath)
+	if !tracked {
+		return
+	}
+
+	if s.matchesAuthoritativeDelete(relPath) {
+		s.tracking.Remove(relPath)
+		s.logger.Info().Str("path", relPath).Msg("Untracked authoritatively deleted STRM file")
+		return
+	}
+
+	if err := s.writeOutput(relPath, entry.DownloadURL, nil); err != nil {
+		s.logger.Error().Err(err).Str("path", relPath).Msg("Failed to recreate deleted STRM file")
+		return
+	}
+	s.logger.Info().Str("path", relPath).Msg("Recreated deleted STRM file")
+}
+
+// matchesAuthoritativeDelete reports whether relPath matches one of the
+// configured authoritative-delete patterns.
+func (s *Service) matchesAuthoritativeDelete(relPath string) bool {
+	for _, pattern := range s.config.AuthoritativeDeletePatterns {
+		if ok, err := filepath.Match(pattern, relPath); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package strm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/config"
+)
+
+// webdav_writer.go talks plain HTTP (PUT/DELETE/HEAD/MKCOL) to a WebDAV
+// server, e.g. a Jellyfin/Plex box exposing its library over WebDAV.
+type webdavWriter struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+}
+
+func newWebDAVWriter(cfg config.WebDAVConfig) *webdavWriter {
+	return &webdavWriter{
+		baseURL:  strings.TrimRight(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (w *webdavWriter) url(p string) string {
+	return w.baseURL + path.Clean("/"+filepath.ToSlash(p))
+}
+
+func (w *webdavWriter) do(method, p string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, w.url(p), reader)
+	if err != nil {
+		return nil, err
+	}
+	if w.username != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+	return w.client.Do(req)
+}
+
+// mkdirAll creates every parent collection of p with MKCOL, ignoring
+// "already exists" responses.
+func (w *webdavWriter) mkdirAll(p string) error {
+	dir := strings.Trim(filepath.ToSlash(filepath.Dir(p)), "/")
+	if dir == "" || dir == "." {
+		return nil
+	}
+
+	cur := ""
+	for _, part := range strings.Split(dir, "/") {
+		cur += "/" + part
+		resp, err := w.do("MKCOL", cur, nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		// 201 Created and 405 Method Not Allowed (collection already exists) are both fine.
+	}
+	return nil
+}
+
+func (w *webdavWriter) Write(p string, data []byte) error {
+	if err := w.mkdirAll(p); err != nil {
+		return fmt.Errorf("webdav mkdir: %w", err)
+	}
+
+	resp, err := w.do(http.MethodPut, p, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webdav PUT %s: %s", p, resp.Status)
+	}
+	return nil
+}
+
+func (w *webdavWriter) Remove(p string) error {
+	resp, err := w.do(http.MethodDelete, p, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("webdav DELETE %s: %s", p, resp.Status)
+	}
+	return nil
+}
+
+func (w *webdavWriter) Stat(p string) (FileInfo, error) {
+	resp, err := w.do(http.MethodHead, p, nil)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return FileInfo{}, os.ErrNotExist
+	}
+	if resp.StatusCode >= 300 {
+		return FileInfo{}, fmt.Errorf("webdav HEAD %s: %s", p, resp.Status)
+	}
+
+	size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	modTime := time.Now()
+	if lm := resp.Header.Get("Last-Modified"); lm != "" {
+		if t, err := http.ParseTime(lm); err == nil {
+			modTime = t
+		}
+	}
+	return FileInfo{Size: size, ModTime: modTime}, nil
+}
+
+func (w *webdavWriter) Backend() string {
+	return "webdav"
+}
@@ -0,0 +1,56 @@
+package strm
+
+import (
+	"time"
+
+	"github.com/robofuse/robofuse/internal/config"
+	"github.com/robofuse/robofuse/internal/logger"
+)
+
+// writer.go defines the pluggable backend STRM files are written through, so
+// a run can target a local mount, a WebDAV/SFTP server, or an rclone remote
+// instead of assuming plain os.* calls.
+
+// FileInfo is the subset of file metadata a Writer reports back.
+type FileInfo struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// Writer is where STRM (or cached content) files physically land.
+// Implementations must treat Remove on a missing path as a no-op and Stat
+// on a missing path as an error satisfying os.IsNotExist.
+type Writer interface {
+	// Write creates or overwrites the file at a path relative to the
+	// backend's root with data, creating parent directories as needed.
+	Write(path string, data []byte) error
+	// Remove deletes the file at path.
+	Remove(path string) error
+	// Stat returns metadata for path.
+	Stat(path string) (FileInfo, error)
+	// Backend identifies the implementation (e.g. "local", "webdav"), so
+	// tracking can record which backend produced a given file.
+	Backend() string
+}
+
+// NewWriter builds the Writer selected by cfg.Output.Backend, rooted at
+// cfg.OutputDir. An unknown or empty backend falls back to the local
+// filesystem so a bad config value degrades gracefully instead of failing
+// the whole service.
+func NewWriter(cfg *config.Config) Writer {
+	log := logger.New("strm")
+
+	switch cfg.Output.Backend {
+	case "", "local":
+		return newLocalWriter(cfg.OutputDir)
+	case "webdav":
+		return newWebDAVWriter(cfg.Output.WebDAV)
+	case "sftp":
+		return newSFTPWriter(cfg.Output.SFTP)
+	case "rclone":
+		return newRcloneWriter(cfg.Output.Rclone)
+	default:
+		log.Warn().Str("backend", cfg.Output.Backend).Msg("Unknown output backend, falling back to local")
+		return newLocalWriter(cfg.OutputDir)
+	}
+}
@@ -1,11 +1,12 @@
 package sync
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
+	"github.com/robofuse/robofuse/internal/debrid"
 	"github.com/robofuse/robofuse/internal/request"
-	"github.com/robofuse/robofuse/pkg/realdebrid"
 )
 
 // RetryStats contains statistics from retry queue processing
@@ -15,38 +16,58 @@ type RetryStats struct {
 	MaxedOut  int
 }
 
-// processRetryQueue processes items from the retry queue
-func (s *Service) processRetryQueue(torrents []*realdebrid.Torrent) *RetryStats {
-	items := s.retryQueue.GetAll()
+// processRetryQueue processes items from the retry queue, routing each one
+// back to the provider account that originally produced it.
+func (s *Service) processRetryQueue(torrentsByProvider map[string][]*debrid.Torrent) *RetryStats {
+	items := s.retryQueue.Ready()
 	if len(items) == 0 {
 		return &RetryStats{}
 	}
 
 	s.logger.Info().Int("count", len(items)).Msg("Processing retry queue")
 
-	// Build torrent map for looking up torrent info
-	torrentMap := make(map[string]*realdebrid.Torrent)
-	for _, t := range torrents {
-		torrentMap[t.ID] = t
+	// Build per-provider torrent maps for looking up torrent info
+	torrentMaps := make(map[string]map[string]*debrid.Torrent, len(torrentsByProvider))
+	for name, torrents := range torrentsByProvider {
+		m := make(map[string]*debrid.Torrent, len(torrents))
+		for _, t := range torrents {
+			m[t.ID] = t
+		}
+		torrentMaps[name] = m
 	}
 
 	stats := &RetryStats{}
 
 	for _, item := range items {
 		// Check if max retries exceeded
-		if item.RetryCount >= s.config.MaxRetryAttempts {
+		if s.retryQueue.MaxedOut(item.Link) {
 			s.logger.Warn().
 				Str("link", item.Link).
 				Str("filename", item.Filename).
 				Int("retries", item.RetryCount).
-				Msg("Max retries exceeded, removing from queue")
-			s.retryQueue.Remove(item.Link)
+				Msg("Max retries exceeded, moving to dead-letter queue")
+			s.retryQueue.Fail(item.Link, "max retries exceeded")
 			stats.MaxedOut++
 			continue
 		}
 
+		providerName := item.Provider
+		if providerName == "" {
+			providerName = "real-debrid"
+		}
+
+		acc, ok := s.providerByName(providerName)
+		if !ok {
+			s.logger.Debug().
+				Str("link", item.Link).
+				Str("provider", providerName).
+				Msg("Provider no longer configured, removing from retry queue")
+			s.retryQueue.Remove(item.Link)
+			continue
+		}
+
 		// Check if torrent still exists
-		if _, exists := torrentMap[item.TorrentID]; !exists {
+		if _, exists := torrentMaps[providerName][item.TorrentID]; !exists {
 			s.logger.Debug().
 				Str("link", item.Link).
 				Msg("Torrent no longer exists, removing from retry queue")
@@ -61,7 +82,7 @@ func (s *Service) processRetryQueue(torrents []*realdebrid.Torrent) *RetryStats
 			Int("attempt", item.RetryCount+1).
 			Msg("Retrying link")
 
-		download, err := s.rd.UnrestrictLink(item.Link)
+		download, err := s.unrestrictViaManager(context.Background(), acc, item.Link)
 		if err != nil {
 			// Check if it's a retryable error (503)
 			if isRetryableError(err) {
@@ -118,7 +139,7 @@ func isRetryableError(err error) bool {
 }
 
 // addToRetryQueue adds a failed link to the retry queue
-func (s *Service) addToRetryQueue(link string, torrent *realdebrid.Torrent, err error) {
+func (s *Service) addToRetryQueue(link string, torrent *debrid.Torrent, provider string, err error) {
 	if !isRetryableError(err) {
 		return // Don't queue non-retryable errors
 	}
@@ -127,6 +148,7 @@ func (s *Service) addToRetryQueue(link string, torrent *realdebrid.Torrent, err
 		link,
 		torrent.ID,
 		torrent.Filename,
+		provider,
 		"503", // Error type
 		err.Error(),
 	)
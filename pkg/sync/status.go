@@ -0,0 +1,308 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/internal/qbittorrent"
+	"github.com/robofuse/robofuse/pkg/metrics"
+	"github.com/robofuse/robofuse/pkg/organizer"
+	"github.com/robofuse/robofuse/pkg/progress"
+	"github.com/robofuse/robofuse/pkg/realdebrid"
+	"github.com/robofuse/robofuse/pkg/retry"
+	"github.com/robofuse/robofuse/pkg/tracking"
+)
+
+// status.go tracks run state for RPC/CLI consumers (robofuse status,
+// pause/resume) and serializes Run so two callers (Watch and a manual
+// trigger) can't race over the service's reused downloadMap/candidates buffers.
+
+// Status is a point-in-time snapshot of the sync service's run state.
+type Status struct {
+	Running     bool      `json:"running"`
+	Paused      bool      `json:"paused"`
+	LastRunAt   time.Time `json:"last_run_at,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastSummary string    `json:"last_summary,omitempty"`
+	RetryQueued int       `json:"retry_queued"`
+	// ExpiringLinks is how many tracked STRM files are due for a link
+	// refresh on the next cycle.
+	ExpiringLinks int `json:"expiring_links"`
+	// OrganizerPending is how many tracked files have no organized
+	// counterpart yet, 0 when PttRename is disabled.
+	OrganizerPending int `json:"organizer_pending"`
+}
+
+// runState tracks whether a cycle is in flight, whether automatic cycles
+// are paused, and the outcome of the most recent cycle.
+type runState struct {
+	mu         sync.Mutex
+	running    bool
+	paused     bool
+	lastResult *RunResult
+	lastRunAt  time.Time
+	lastErr    string
+}
+
+// Status returns a snapshot of the service's current run state.
+func (s *Service) Status() Status {
+	s.runState.mu.Lock()
+	defer s.runState.mu.Unlock()
+
+	st := Status{
+		Running:     s.runState.running,
+		Paused:      s.runState.paused,
+		LastRunAt:   s.runState.lastRunAt,
+		LastError:   s.runState.lastErr,
+		RetryQueued: s.retryQueue.Count(),
+	}
+	if s.runState.lastResult != nil {
+		st.LastSummary = FormatSummary(s.runState.lastResult, SummaryOptions{})
+	}
+
+	expiryDuration := time.Duration(s.config.FileExpiryDays) * 24 * time.Hour
+	st.ExpiringLinks = len(s.strmService.GetExpiredFiles(expiryDuration))
+
+	if s.config.PttRename {
+		if pending, err := s.organizerPendingCount(); err == nil {
+			st.OrganizerPending = pending
+		}
+	}
+
+	return st
+}
+
+// organizerPendingCount builds a throwaway Organizer sharing the service's
+// store and asks it for a cheap pending-work count, so Status doesn't need
+// to run a full sweep just to answer "is there organizer work queued".
+func (s *Service) organizerPendingCount() (int, error) {
+	org := organizer.New(organizer.Config{
+		BaseDir:      s.config.Path,
+		OrganizedDir: s.config.OrganizedDir,
+		OutputDir:    s.config.OutputDir,
+		CacheDir:     s.config.CacheDir,
+		Store:        s.store,
+		Logger:       s.logger,
+	})
+	return org.PendingCount()
+}
+
+// Pause stops Watch from starting new automatic cycles until Resume is called.
+func (s *Service) Pause() {
+	s.runState.mu.Lock()
+	s.runState.paused = true
+	s.runState.mu.Unlock()
+}
+
+// Resume allows Watch to resume automatic cycles.
+func (s *Service) Resume() {
+	s.runState.mu.Lock()
+	s.runState.paused = false
+	s.runState.mu.Unlock()
+}
+
+// Paused reports whether automatic cycles are currently paused.
+func (s *Service) Paused() bool {
+	s.runState.mu.Lock()
+	defer s.runState.mu.Unlock()
+	return s.runState.paused
+}
+
+// TriggerSync runs a sync cycle immediately, rejecting the call if one is
+// already in progress. It blocks for the duration of the run, so callers
+// that shouldn't block (e.g. an RPC handler) should invoke it from their
+// own goroutine.
+func (s *Service) TriggerSync(ctx context.Context, dryRun bool) (*RunResult, error) {
+	s.runState.mu.Lock()
+	if s.runState.running {
+		s.runState.mu.Unlock()
+		return nil, fmt.Errorf("sync already in progress")
+	}
+	s.runState.running = true
+	s.runState.mu.Unlock()
+
+	defer func() {
+		s.runState.mu.Lock()
+		s.runState.running = false
+		s.runState.mu.Unlock()
+	}()
+
+	result, err := s.Run(ctx, dryRun)
+
+	s.runState.mu.Lock()
+	s.runState.lastRunAt = time.Now()
+	if err != nil {
+		s.runState.lastErr = err.Error()
+	} else {
+		s.runState.lastErr = ""
+		s.runState.lastResult = result
+	}
+	s.runState.mu.Unlock()
+
+	return result, err
+}
+
+// RefreshExpiringLinks immediately refreshes links due to expire, rejecting
+// the call if a sync cycle is already in progress for the same reason
+// TriggerSync does: both contend for the service's reused buffers.
+func (s *Service) RefreshExpiringLinks(ctx context.Context) error {
+	s.runState.mu.Lock()
+	if s.runState.running {
+		s.runState.mu.Unlock()
+		return fmt.Errorf("sync already in progress")
+	}
+	s.runState.running = true
+	s.runState.mu.Unlock()
+
+	defer func() {
+		s.runState.mu.Lock()
+		s.runState.running = false
+		s.runState.mu.Unlock()
+	}()
+
+	interval := time.Duration(s.config.WatchModeInterval) * time.Second
+	s.refreshExpiringLinks(ctx, interval)
+	return nil
+}
+
+// RefreshFile immediately refreshes a single tracked STRM file's download
+// link, for the web dashboard's force-refresh button. It rejects the call
+// while a sync cycle is already in progress, for the same reason
+// TriggerSync does: both contend for the service's reused buffers.
+func (s *Service) RefreshFile(ctx context.Context, relativePath string) error {
+	s.runState.mu.Lock()
+	if s.runState.running {
+		s.runState.mu.Unlock()
+		return fmt.Errorf("sync already in progress")
+	}
+	s.runState.running = true
+	s.runState.mu.Unlock()
+
+	defer func() {
+		s.runState.mu.Lock()
+		s.runState.running = false
+		s.runState.mu.Unlock()
+	}()
+
+	tr, ok := s.strmService.TrackingSnapshot()[relativePath]
+	if !ok {
+		return fmt.Errorf("no tracked file at %q", relativePath)
+	}
+
+	providerName := tr.Provider
+	if providerName == "" {
+		providerName = "real-debrid"
+	}
+	acc, ok := s.providerByName(providerName)
+	if !ok {
+		return fmt.Errorf("provider %q is no longer configured", providerName)
+	}
+
+	download, err := s.unrestrictViaManager(ctx, acc, tr.Link)
+	if err != nil {
+		return fmt.Errorf("refreshing link: %w", err)
+	}
+
+	return s.strmService.UpdateSTRM(tr.RelativePath, download.Download, tr.Link, tr.TorrentID, providerName)
+}
+
+// ResolveLink re-unrestricts link through the named provider account, for
+// pkg/mount's on-demand FUSE reads. Unlike RefreshFile it doesn't touch the
+// STRM output or tracking - the caller already has its own fresher copy of
+// whatever it's reading. Empty provider defaults to "real-debrid", matching
+// every other tracked-entry provider fallback.
+func (s *Service) ResolveLink(ctx context.Context, provider, link string) (string, error) {
+	if provider == "" {
+		provider = "real-debrid"
+	}
+	acc, ok := s.providerByName(provider)
+	if !ok {
+		return "", fmt.Errorf("provider %q is no longer configured", provider)
+	}
+
+	download, err := s.unrestrictViaManager(ctx, acc, link)
+	if err != nil {
+		return "", fmt.Errorf("resolving link: %w", err)
+	}
+	return download.Download, nil
+}
+
+// FlushRetryQueue forces every queued retry to be picked up by the next
+// cycle regardless of its backoff, returning how many items were affected.
+func (s *Service) FlushRetryQueue() int {
+	n := s.retryQueue.MarkAllReady()
+	if err := s.retryQueue.Save(); err != nil {
+		s.logger.Warn().Err(err).Msg("Failed to save retry queue after flush")
+	}
+	return n
+}
+
+// RetryQueueItems returns a snapshot of the pending retry queue.
+func (s *Service) RetryQueueItems() []*retry.RetryItem {
+	return s.retryQueue.GetAll()
+}
+
+// DeadLetterQueueItems returns a snapshot of permanently failed retry items.
+func (s *Service) DeadLetterQueueItems() []*retry.DeadLetterItem {
+	return s.retryQueue.DeadLetter()
+}
+
+// ClearDeadLetterQueue discards every dead-letter item.
+func (s *Service) ClearDeadLetterQueue() error {
+	return s.retryQueue.ClearDeadLetter()
+}
+
+// Progress returns the hub batch operations publish progress events to.
+func (s *Service) Progress() *progress.Hub {
+	return s.progress
+}
+
+// Metrics returns the Prometheus metrics registry, for the /metrics server.
+func (s *Service) Metrics() *metrics.Registry {
+	return s.metrics
+}
+
+// TrackingSnapshot returns a copy of all tracked STRM files, satisfying
+// metrics.DebugProvider for the /debug/queue endpoint.
+func (s *Service) TrackingSnapshot() map[string]*tracking.FileTracking {
+	return s.strmService.TrackingSnapshot()
+}
+
+// Tracking returns the live tracking store backing STRM output, for
+// callers like pkg/mount that need to serve the tracked set as it's
+// updated rather than a point-in-time snapshot.
+func (s *Service) Tracking() *tracking.Service {
+	return s.strmService.Tracking()
+}
+
+// RealDebridClient returns the Real-Debrid client, for the qBittorrent shim.
+func (s *Service) RealDebridClient() *realdebrid.Client {
+	return s.rd
+}
+
+// ProviderByName resolves name to its configured debrid.Provider, for
+// callers outside this package (e.g. the qBittorrent BT_backup exporter)
+// that need to route a lookup to the account that produced a given
+// tracked torrent instead of always hitting the primary one.
+func (s *Service) ProviderByName(name string) (debrid.Provider, bool) {
+	acc, ok := s.providerByName(name)
+	if !ok {
+		return nil, false
+	}
+	return acc.provider, true
+}
+
+// QBittorrentStore returns the infohash -> Real-Debrid torrent store backing
+// the qBittorrent shim.
+func (s *Service) QBittorrentStore() *qbittorrent.Store {
+	return s.qbStore
+}
+
+// qbittorrentCategory resolves torrentID's category via the qBittorrent
+// shim's store, satisfying organizer.CategoryLookup.
+func (s *Service) qbittorrentCategory(torrentID string) string {
+	return s.qbStore.CategoryFor(torrentID)
+}
@@ -45,6 +45,13 @@ func FormatSummary(result *RunResult, opts SummaryOptions) string {
 
 	parts = append(parts, fmt.Sprintf("strm_created=%d strm_updated=%d strm_removed=%d strm_unchanged=%d", result.STRMAdded, result.STRMUpdated, result.STRMDeleted, result.STRMSkipped))
 
+	if result.DiskPaused {
+		parts = append(parts, fmt.Sprintf("disk_paused=1 disk_space_pauses=%d", result.DiskSpacePauses))
+	}
+	if result.STRMDiskSkipped > 0 {
+		parts = append(parts, fmt.Sprintf("strm_disk_skipped=%d", result.STRMDiskSkipped))
+	}
+
 	if opts.IncludeOrg {
 		parts = append(parts, fmt.Sprintf("org_created=%d org_updated=%d org_removed=%d", result.OrgNew, result.OrgUpdated, result.OrgDeleted))
 	}
@@ -1,53 +1,178 @@
 package sync
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/robofuse/robofuse/internal/breaker"
 	"github.com/robofuse/robofuse/internal/config"
 	"github.com/robofuse/robofuse/internal/console"
+	"github.com/robofuse/robofuse/internal/debrid"
+	"github.com/robofuse/robofuse/internal/diskspace"
 	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/internal/qbittorrent"
+	"github.com/robofuse/robofuse/internal/quota"
 	"github.com/robofuse/robofuse/internal/request"
+	"github.com/robofuse/robofuse/internal/store"
+	"github.com/robofuse/robofuse/pkg/metrics"
 	"github.com/robofuse/robofuse/pkg/organizer"
+	"github.com/robofuse/robofuse/pkg/progress"
 	"github.com/robofuse/robofuse/pkg/realdebrid"
 	"github.com/robofuse/robofuse/pkg/repair"
 	"github.com/robofuse/robofuse/pkg/retry"
 	"github.com/robofuse/robofuse/pkg/strm"
-	"github.com/robofuse/robofuse/pkg/worker"
+	"github.com/robofuse/robofuse/pkg/xfer"
 	"github.com/rs/zerolog"
 )
 
 // sync.go orchestrates full sync cycles, watch mode, and summary reporting.
 
+// providerAccount pairs a configured debrid account with the per-account
+// state a sync cycle drives it through: its own repair service (so a dead
+// torrent is re-added on the account that owns it) and its own circuit
+// breaker (so one account's outage doesn't short-circuit the others).
+type providerAccount struct {
+	name     string
+	provider debrid.Provider
+	repair   *repair.Service
+	breaker  *breaker.Breaker
+	// quota tracks how often this account has recently hit its hoster
+	// traffic allowance, so repair can refuse to reinsert into an account
+	// that's still over that ceiling.
+	quota *quota.Tracker
+}
+
 // Service orchestrates the entire sync process
 type Service struct {
-	rd            *realdebrid.Client
-	repairService *repair.Service
-	strmService   *strm.Service
-	retryQueue    *retry.Queue
-	config        *config.Config
-	logger        zerolog.Logger
+	rd          *realdebrid.Client
+	providers   []*providerAccount
+	strmService *strm.Service
+	retryQueue  *retry.Queue
+	diskGuard   *diskspace.Guard
+	xferManager *xfer.Manager[*debrid.Download]
+	progress    *progress.Hub
+	metrics     *metrics.Registry
+	qbStore     *qbittorrent.Store
+	store       *store.DB
+	config      *config.Config
+	logger      zerolog.Logger
 	// Reusable allocations for watch mode
-	downloadMap map[string]*realdebrid.Download
+	downloadMap map[string]*debrid.Download
 	candidates  []realdebrid.STRMCandidate
+	// run state for RPC/CLI status and pause/resume
+	runState runState
 }
 
 // New creates a new sync service
-func New(cfg *config.Config) *Service {
-	rd := realdebrid.New(cfg)
+func New(cfg *config.Config) (*Service, error) {
+	reg := metrics.NewRegistry()
+	rd := realdebrid.New(cfg, reg)
+
+	db, err := store.Open(filepath.Join(cfg.CacheDir, "robofuse.db"))
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+
+	diskGuard := diskspace.NewGuard(diskspace.GuardConfig{
+		Paths:           cfg.DiskSpaceGuardPaths(),
+		MinFreeBytes:    cfg.DiskSpaceMinFreeBytes,
+		MinFreePercent:  cfg.DiskSpaceMinFreePercent,
+		CheckInterval:   time.Duration(cfg.DiskSpaceCheckIntervalSec) * time.Second,
+		BackoffOnLow:    time.Duration(cfg.DiskSpaceBackoffSec) * time.Second,
+		MaxBackoffOnLow: time.Duration(cfg.DiskSpaceMaxBackoffSec) * time.Second,
+	})
+
+	providers, err := buildProviders(cfg, rd, diskGuard)
+	if err != nil {
+		return nil, fmt.Errorf("building debrid providers: %w", err)
+	}
+
+	xferManager := xfer.NewManager[*debrid.Download](xfer.BackoffConfig{
+		Base:        2 * time.Second,
+		Max:         30 * time.Second,
+		Jitter:      0.25,
+		MaxAttempts: 3,
+	})
+
+	progressHub := progress.NewHub()
 
 	return &Service{
-		rd:            rd,
-		repairService: repair.New(rd, cfg),
-		strmService:   strm.New(cfg),
-		retryQueue:    retry.New(cfg.RetryQueueFile),
-		config:        cfg,
-		logger:        logger.New("sync"),
-		downloadMap:   make(map[string]*realdebrid.Download),
-		candidates:    make([]realdebrid.STRMCandidate, 0, 1024),
+		rd:          rd,
+		providers:   providers,
+		strmService: strm.New(cfg, rd.DownloadsClient(), progressHub, db),
+		retryQueue:  retry.New(cfg.RetryQueueFile, cfg.RetryDeadLetterFile, cfg.MaxRetryAttempts),
+		diskGuard:   diskGuard,
+		xferManager: xferManager,
+		progress:    progressHub,
+		metrics:     reg,
+		qbStore:     qbittorrent.NewStore(filepath.Join(cfg.CacheDir, "qbittorrent_store.json")),
+		store:       db,
+		config:      cfg,
+		logger:      logger.New("sync"),
+		downloadMap: make(map[string]*debrid.Download),
+		candidates:  make([]realdebrid.STRMCandidate, 0, 1024),
+	}, nil
+}
+
+// buildProviders resolves cfg's configured debrid accounts into
+// providerAccounts. The primary "real-debrid" entry is wired to the
+// already-constructed rd client instead of going through debrid.Registry,
+// since realdebrid.Client needs *config.Config and *metrics.Registry to
+// construct, not just an API token - every other entry is built through
+// debrid.New(name, token).
+func buildProviders(cfg *config.Config, rd *realdebrid.Client, diskGuard *diskspace.Guard) ([]*providerAccount, error) {
+	resolved := cfg.ResolvedProviders()
+	accounts := make([]*providerAccount, 0, len(resolved))
+	primaryWired := false
+
+	for _, pc := range resolved {
+		var p debrid.Provider
+		if pc.Name == "real-debrid" && !primaryWired {
+			p = realdebrid.NewAdapter(rd)
+			primaryWired = true
+		} else {
+			provider, err := debrid.New(pc.Name, pc.Token)
+			if err != nil {
+				return nil, fmt.Errorf("provider %q: %w", pc.Name, err)
+			}
+			p = provider
+		}
+
+		quotaTracker := quota.New(quota.Config{
+			Threshold: cfg.TrafficQuotaThreshold,
+			Window:    time.Duration(cfg.TrafficQuotaWindowSec) * time.Second,
+		})
+
+		accounts = append(accounts, &providerAccount{
+			name:     p.Name(),
+			provider: p,
+			repair:   repair.New(p, cfg, diskGuard, quotaTracker),
+			breaker: breaker.New(breaker.Config{
+				FailureThreshold: cfg.CircuitBreakerFailureThreshold,
+				Window:           time.Duration(cfg.CircuitBreakerWindowSec) * time.Second,
+				ResetTimeout:     time.Duration(cfg.CircuitBreakerResetSec) * time.Second,
+			}),
+			quota: quotaTracker,
+		})
+	}
+
+	return accounts, nil
+}
+
+// providerByName finds the configured account matching name, used to route
+// retries and expiring-link refreshes back to the account that produced
+// them.
+func (s *Service) providerByName(name string) (*providerAccount, bool) {
+	for _, acc := range s.providers {
+		if acc.name == name {
+			return acc, true
+		}
 	}
+	return nil, false
 }
 
 // RunResult contains the results of a sync run
@@ -65,6 +190,9 @@ type RunResult struct {
 	STRMUpdated        int
 	STRMDeleted        int
 	STRMSkipped        int
+	STRMDiskSkipped    int
+	DiskPaused         bool
+	DiskSpacePauses    int
 	Duration           time.Duration
 	// Organizer results
 	OrgProcessed int
@@ -74,26 +202,57 @@ type RunResult struct {
 	OrgErrors    int
 }
 
-// Run executes the sync process
-func (s *Service) Run(dryRun bool) (*RunResult, error) {
+// Run executes the sync process. ctx is checked between stages so a
+// cancelled context (e.g. on shutdown) stops the cycle at the next
+// boundary instead of partway through a stage. Every configured debrid
+// account is swept in turn and merged into one STRM sync, so an account
+// that's down for the cycle (breaker open, fetch error) is simply skipped
+// rather than failing the whole run.
+func (s *Service) Run(ctx context.Context, dryRun bool) (*RunResult, error) {
 	startTime := time.Now()
 	result := &RunResult{}
 
 	s.logger.Debug().Msg("Starting sync...")
 
-	// Step 1: Fetch all torrents
+	// Step 1: Fetch all torrents from every configured account
 	s.logger.Debug().Msg("Fetching torrents...")
-	downloaded, dead, err := s.rd.GetTorrents()
-	if err != nil {
-		return nil, fmt.Errorf("fetching torrents: %w", err)
+	downloadedByProvider := make(map[string][]*debrid.Torrent, len(s.providers))
+	deadByProvider := make(map[string][]*debrid.Torrent, len(s.providers))
+	for _, acc := range s.providers {
+		if !acc.breaker.Allow() {
+			s.logger.Warn().Str("provider", acc.name).Msg("Provider calls are failing, skipping this cycle")
+			continue
+		}
+
+		downloaded, dead, err := acc.provider.GetTorrents()
+		if err != nil {
+			acc.breaker.RecordFailure()
+			s.logger.Warn().Err(err).Str("provider", acc.name).Msg("Fetching torrents failed")
+			continue
+		}
+		acc.breaker.RecordSuccess()
+
+		downloadedByProvider[acc.name] = downloaded
+		deadByProvider[acc.name] = dead
+		result.TorrentsDownloaded += len(downloaded)
+		result.TorrentsDead += len(dead)
+		result.TorrentsTotal += len(downloaded) + len(dead)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	result.TorrentsDownloaded = len(downloaded)
-	result.TorrentsDead = len(dead)
-	result.TorrentsTotal = result.TorrentsDownloaded + result.TorrentsDead
 
 	// Step 2: Process retry queue (cross-cycle retries)
 	if !dryRun {
-		retryStats := s.processRetryQueue(downloaded)
+		if waited, err := s.diskGuard.Allow(ctx); err != nil {
+			return nil, fmt.Errorf("waiting for disk space: %w", err)
+		} else if waited {
+			result.DiskPaused = true
+			result.DiskSpacePauses++
+		}
+
+		retryStats := s.processRetryQueue(downloadedByProvider)
 		if retryStats.Succeeded > 0 {
 			s.logger.Info().
 				Int("succeeded", retryStats.Succeeded).
@@ -103,100 +262,134 @@ func (s *Service) Run(dryRun bool) (*RunResult, error) {
 	}
 
 	// Step 3: Repair dead torrents if enabled
-	if s.config.RepairTorrents && len(dead) > 0 {
-		s.logger.Debug().Int("count", len(dead)).Msg("Repairing dead torrents...")
-		repaired, _ := s.repairService.RepairTorrents(dead, dryRun)
-		result.TorrentsRepaired = repaired
-
-		// Re-fetch torrents after repair
-		if repaired > 0 && !dryRun {
-			downloaded, _, err = s.rd.GetTorrents()
-			if err != nil {
-				s.logger.Warn().Err(err).Msg("Failed to re-fetch torrents after repair")
+	if s.config.RepairTorrents {
+		for _, acc := range s.providers {
+			dead := deadByProvider[acc.name]
+			if len(dead) == 0 {
+				continue
+			}
+
+			s.logger.Debug().Int("count", len(dead)).Str("provider", acc.name).Msg("Repairing dead torrents...")
+			repaired, _ := acc.repair.RepairTorrents(dead, dryRun)
+			result.TorrentsRepaired += repaired
+
+			if repaired > 0 && !dryRun {
+				downloaded, _, err := acc.provider.GetTorrents()
+				if err != nil {
+					s.logger.Warn().Err(err).Str("provider", acc.name).Msg("Failed to re-fetch torrents after repair")
+				} else {
+					downloadedByProvider[acc.name] = downloaded
+				}
 			}
 		}
 	}
 
-	// Step 4: Fetch all downloads
-	s.logger.Debug().Msg("Fetching downloads...")
-	downloads, err := s.rd.GetDownloads()
-	if err != nil {
-		return nil, fmt.Errorf("fetching downloads: %w", err)
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	result.DownloadsTotal = len(downloads)
 
-	// Step 4: Build link -> download map (reuse existing map)
+	// Step 4: Fetch downloads, match links, unrestrict missing ones, and
+	// build STRM candidates, per account, merged into one cycle.
 	s.logger.Debug().Msg("Matching torrents to downloads...")
 	clear(s.downloadMap)
-	for _, d := range downloads {
-		s.downloadMap[d.Link] = d
-	}
+	s.candidates = s.candidates[:0]
+	var stats candidateStats
 
-	// Step 5: Find links needing unrestriction
-	var missingLinks []missingLink
-	for _, torrent := range downloaded {
-		for _, link := range torrent.Links {
-			if _, exists := s.downloadMap[link]; !exists {
-				missingLinks = append(missingLinks, missingLink{
-					torrent: torrent,
-					link:    link,
-				})
-			}
+	for _, acc := range s.providers {
+		downloaded := downloadedByProvider[acc.name]
+		if downloaded == nil {
+			continue
 		}
-	}
 
-	s.logger.Debug().
-		Int("total_torrent_links", countTotalLinks(downloaded)).
-		Int("existing_downloads", len(s.downloadMap)).
-		Int("missing", len(missingLinks)).
-		Msg("Link matching complete")
-
-	if logger.IsInfoEnabled() {
-		s.logger.Info().Msgf("discovery | torrents_downloaded=%d torrents_dead=%d downloads_cached=%d missing_links=%d",
-			result.TorrentsDownloaded, result.TorrentsDead, result.DownloadsTotal, len(missingLinks))
-		if logger.IsTTY() {
-			fmt.Println()
+		if waited, err := s.diskGuard.Allow(ctx); err != nil {
+			return nil, fmt.Errorf("waiting for disk space: %w", err)
+		} else if waited {
+			result.DiskPaused = true
+			result.DiskSpacePauses++
 		}
-	}
 
-	// Step 6: Unrestrict missing links
-	if len(missingLinks) > 0 {
-		s.logger.Debug().Int("count", len(missingLinks)).Msg("Unrestricting missing links...")
-
-		unrestricted, failed, queued := s.unrestrictLinks(missingLinks, dryRun)
-		result.LinksUnrestricted = len(unrestricted)
-		result.LinksFailed = len(failed)
-		result.LinksQueued = queued
+		downloads, err := acc.provider.GetDownloads()
+		if err != nil {
+			acc.breaker.RecordFailure()
+			s.logger.Warn().Err(err).Str("provider", acc.name).Msg("Fetching downloads failed")
+			continue
+		}
+		acc.breaker.RecordSuccess()
+		result.DownloadsTotal += len(downloads)
 
-		// Add new downloads to map
-		for _, d := range unrestricted {
+		downloadMap := make(map[string]*debrid.Download, len(downloads))
+		for _, d := range downloads {
+			downloadMap[d.Link] = d
 			s.downloadMap[d.Link] = d
 		}
 
-		// Handle failed links - mark torrents for repair
-		if len(failed) > 0 && s.config.RepairTorrents && !dryRun {
-			failedTorrents := s.findTorrentsForLinks(downloaded, failed)
-			if len(failedTorrents) > 0 {
-				s.logger.Debug().Int("count", len(failedTorrents)).Msg("Repairing torrents with failed links...")
-				s.repairService.RepairTorrents(failedTorrents, dryRun)
+		var missingLinks []missingLink
+		for _, torrent := range downloaded {
+			for _, link := range torrent.Links {
+				if _, exists := downloadMap[link]; !exists {
+					missingLinks = append(missingLinks, missingLink{torrent: torrent, link: link})
+				}
 			}
 		}
+
+		s.logger.Debug().
+			Str("provider", acc.name).
+			Int("total_torrent_links", countTotalLinks(downloaded)).
+			Int("existing_downloads", len(downloadMap)).
+			Int("missing", len(missingLinks)).
+			Msg("Link matching complete")
+
+		if len(missingLinks) > 0 {
+			s.logger.Debug().Int("count", len(missingLinks)).Str("provider", acc.name).Msg("Unrestricting missing links...")
+
+			unrestricted, failed, queued := s.unrestrictLinks(ctx, acc, missingLinks, dryRun)
+			result.LinksUnrestricted += len(unrestricted)
+			result.LinksFailed += len(failed)
+			result.LinksQueued += queued
+			s.metrics.AddLinksUnrestricted(len(unrestricted))
+			s.metrics.AddLinksFailed(len(failed))
+
+			for _, d := range unrestricted {
+				downloadMap[d.Link] = d
+				s.downloadMap[d.Link] = d
+			}
+
+			if len(failed) > 0 && s.config.RepairTorrents && !dryRun {
+				failedTorrents := s.findTorrentsForLinks(downloaded, failed)
+				if len(failedTorrents) > 0 {
+					s.logger.Debug().Int("count", len(failedTorrents)).Str("provider", acc.name).Msg("Repairing torrents with failed links...")
+					acc.repair.RepairTorrents(failedTorrents, dryRun)
+				}
+			}
+		}
+
+		s.candidates = s.buildCandidatesInto(acc.name, downloaded, downloadMap, s.candidates, &stats)
 	}
 
 	result.DownloadsAfter = len(s.downloadMap)
-
-	// Step 7: Build STRM candidates (reuse existing slice)
-	s.logger.Debug().Msg("Building STRM candidates...")
-	var stats candidateStats
-	s.candidates = s.buildCandidatesInto(downloaded, s.downloadMap, s.candidates[:0], &stats)
 	s.logger.Debug().Int("count", len(s.candidates)).Msg("STRM candidates ready")
 
 	if logger.IsInfoEnabled() {
+		s.logger.Info().Msgf("discovery | torrents_downloaded=%d torrents_dead=%d downloads_cached=%d",
+			result.TorrentsDownloaded, result.TorrentsDead, result.DownloadsTotal)
 		s.logger.Info().Msgf("strm_sync | candidates=%d filtered_small=%d filtered_other=%d", stats.Candidates, stats.FilteredSmall, stats.FilteredOther)
+		if logger.IsTTY() {
+			fmt.Println()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// Step 8: Sync STRM files
 	s.logger.Debug().Msg("Syncing STRM files...")
+	if waited, err := s.diskGuard.Allow(ctx); err != nil {
+		return nil, fmt.Errorf("waiting for disk space: %w", err)
+	} else if waited {
+		result.DiskPaused = true
+		result.DiskSpacePauses++
+	}
 	strmResult, err := s.strmService.Sync(s.candidates, dryRun)
 	if err != nil {
 		return nil, fmt.Errorf("syncing STRM files: %w", err)
@@ -205,15 +398,19 @@ func (s *Service) Run(dryRun bool) (*RunResult, error) {
 	result.STRMUpdated = strmResult.Updated
 	result.STRMDeleted = strmResult.Deleted
 	result.STRMSkipped = strmResult.Skipped
+	result.STRMDiskSkipped = strmResult.DiskSkipped
 	if logger.IsInfoEnabled() {
-		s.logger.Info().Msgf("strm_results | created=%d updated=%d removed=%d unchanged=%d tracked=%d",
-			result.STRMAdded, result.STRMUpdated, result.STRMDeleted, result.STRMSkipped, strmResult.Tracked)
+		s.logger.Info().Msgf("strm_results | created=%d updated=%d removed=%d unchanged=%d disk_skipped=%d tracked=%d",
+			result.STRMAdded, result.STRMUpdated, result.STRMDeleted, result.STRMSkipped, result.STRMDiskSkipped, strmResult.Tracked)
 		if logger.IsTTY() {
 			fmt.Println()
 		}
 	}
 
 	result.Duration = time.Since(startTime)
+	s.metrics.SetRetryQueueDepth(s.retryQueue.Count())
+	s.metrics.SetSTRMFiles(strmResult.Tracked)
+	s.metrics.ObserveSyncDuration(result.Duration.Seconds())
 
 	s.logger.Debug().
 		Int("strm_added", result.STRMAdded).
@@ -223,8 +420,8 @@ func (s *Service) Run(dryRun bool) (*RunResult, error) {
 		Msg("Sync completed")
 
 	// PTT Rename / Organize
-	if s.config.PttRename && !dryRun {
-		orgResult := s.runOrganizer()
+	if s.config.PttRename && !dryRun && ctx.Err() == nil {
+		orgResult := s.runOrganizer(ctx)
 		result.OrgProcessed = orgResult.Processed
 		result.OrgNew = orgResult.New
 		result.OrgDeleted = orgResult.Deleted
@@ -237,42 +434,108 @@ func (s *Service) Run(dryRun bool) (*RunResult, error) {
 	}
 
 	// Refresh expiring links (works in both manual and watch mode)
-	if !dryRun {
+	if !dryRun && ctx.Err() == nil {
 		interval := time.Duration(s.config.WatchModeInterval) * time.Second
-		s.refreshExpiringLinks(interval)
+		s.refreshExpiringLinks(ctx, interval)
 	}
 
 	return result, nil
 
 }
 
-// Watch runs the sync process in a loop
-func (s *Service) Watch() error {
+// Watch runs the sync process in a loop until ctx is cancelled, triggering
+// a cycle every WatchModeInterval and, when config.WatchDir is set,
+// immediately after a dropped magnet/torrent file is submitted to
+// Real-Debrid. On cancellation it lets an in-flight cycle drain, persists
+// the retry queue, and returns nil - a cancelled ctx is a clean shutdown,
+// not a failure.
+func (s *Service) Watch(ctx context.Context) error {
 	interval := time.Duration(s.config.WatchModeInterval) * time.Second
+	breakerWait := interval
 
 	s.logger.Info().
 		Dur("interval", interval).
 		Msg("Starting watch mode")
 
-	for {
-		result, err := s.Run(false)
+	// runCycle returns how long to wait before the next cycle: normally
+	// interval, but doubled (capped) on each consecutive circuit-breaker
+	// trip so a Real-Debrid outage doesn't get hammered with probe cycles.
+	runCycle := func() time.Duration {
+		if s.Paused() {
+			s.logger.Debug().Msg("Sync paused, skipping cycle")
+			return interval
+		}
+
+		result, err := s.TriggerSync(ctx, false)
 		if err != nil {
-			s.logger.Error().Err(err).Msg("Sync failed")
-		} else {
-			// Print clean cycle summary
-			s.printCycleSummary(result, interval)
+			if errors.Is(err, breaker.ErrOpen) {
+				breakerWait *= 2
+				if breakerWait > maxBreakerBackoff {
+					breakerWait = maxBreakerBackoff
+				}
+				s.logger.Warn().Dur("next_probe", breakerWait).Msg("Real-Debrid circuit open, backing off")
+				return breakerWait
+			}
+			if ctx.Err() == nil {
+				s.logger.Error().Err(err).Msg("Sync failed")
+			}
+			return interval
+		}
+
+		breakerWait = interval
+		s.printCycleSummary(result, interval)
+		return interval
+	}
+
+	watcher, err := s.startIngestWatcher(func() { runCycle() })
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Failed to start watch-dir watcher, continuing on timer only")
+	}
+	if watcher != nil {
+		defer watcher.Close()
+	}
+
+	for {
+		wait := runCycle()
+
+		select {
+		case <-ctx.Done():
+			return s.shutdown()
+		default:
 		}
 
 		s.logger.Info().
-			Time("next_run", time.Now().Add(interval)).
+			Time("next_run", time.Now().Add(wait)).
 			Msg("Waiting for next cycle")
 
-		time.Sleep(interval)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return s.shutdown()
+		case <-timer.C:
+		}
+	}
+}
+
+// maxBreakerBackoff caps how long Watch waits between probe cycles while
+// the Real-Debrid circuit breaker stays open.
+const maxBreakerBackoff = 10 * time.Minute
+
+// shutdown persists cross-cycle state that would otherwise be lost between
+// the last completed cycle and the next process start, then logs a clean
+// exit. The STRM tracking store is bbolt-backed and already durable after
+// every write, so only the retry queue needs an explicit flush here.
+func (s *Service) shutdown() error {
+	s.logger.Info().Msg("Shutting down, persisting retry queue")
+	if err := s.retryQueue.Save(); err != nil {
+		return fmt.Errorf("persisting retry queue on shutdown: %w", err)
 	}
+	return nil
 }
 
 // refreshExpiringLinks refreshes links that will expire before the next run
-func (s *Service) refreshExpiringLinks(interval time.Duration) {
+func (s *Service) refreshExpiringLinks(ctx context.Context, interval time.Duration) {
 	// Get files older than configured expiry days
 	expiryDuration := time.Duration(s.config.FileExpiryDays) * 24 * time.Hour
 	expiredFiles := s.strmService.GetExpiredFiles(expiryDuration)
@@ -284,23 +547,38 @@ func (s *Service) refreshExpiringLinks(interval time.Duration) {
 	s.logger.Info().Int("count", len(expiredFiles)).Msg("Refreshing expired links")
 
 	var refreshed, failed int
-	for _, tracking := range expiredFiles {
+	for _, tr := range expiredFiles {
+		if ctx.Err() != nil {
+			break
+		}
+
+		providerName := tr.Provider
+		if providerName == "" {
+			providerName = "real-debrid"
+		}
+		acc, ok := s.providerByName(providerName)
+		if !ok {
+			s.logger.Warn().Str("path", tr.RelativePath).Str("provider", providerName).Msg("Tracked file's provider is no longer configured, skipping refresh")
+			failed++
+			continue
+		}
+
 		// Unrestrict the original link to get a fresh download URL
-		download, err := s.rd.UnrestrictLink(tracking.Link)
+		download, err := s.unrestrictViaManager(ctx, acc, tr.Link)
 		if err != nil {
 			s.logger.Warn().
 				Err(err).
-				Str("path", tracking.RelativePath).
+				Str("path", tr.RelativePath).
 				Msg("Failed to refresh expired link")
 			failed++
 			continue
 		}
 
 		// Update the STRM file with the new URL
-		if err := s.strmService.UpdateSTRM(tracking.RelativePath, download.Download, tracking.Link, tracking.TorrentID); err != nil {
+		if err := s.strmService.UpdateSTRM(tr.RelativePath, download.Download, tr.Link, tr.TorrentID, providerName); err != nil {
 			s.logger.Warn().
 				Err(err).
-				Str("path", tracking.RelativePath).
+				Str("path", tr.RelativePath).
 				Msg("Failed to update STRM file")
 			failed++
 		} else {
@@ -332,35 +610,85 @@ func (s *Service) printCycleSummary(result *RunResult, interval time.Duration) {
 
 // missingLink represents a link that needs unrestriction
 type missingLink struct {
-	torrent *realdebrid.Torrent
+	torrent *debrid.Torrent
 	link    string
 }
 
-// unrestrictLinks unrestricts multiple links concurrently
-func (s *Service) unrestrictLinks(links []missingLink, dryRun bool) ([]*realdebrid.Download, []string, int) {
+// unrestrictViaManager submits an unrestrict call through the transfer
+// manager so that a fresh sync and a queued retry racing on the same link
+// share a single in-flight call and backoff, instead of each hammering the
+// provider independently. Only 5xx-class failures feed acc's circuit
+// breaker - a single hoster-unavailable or traffic-exceeded link isn't an
+// account outage signal.
+func (s *Service) unrestrictViaManager(ctx context.Context, acc *providerAccount, link string) (*debrid.Download, error) {
+	ch, cancel := s.xferManager.Watch(ctx, link, func(ctx context.Context) (*debrid.Download, error) {
+		return acc.provider.UnrestrictLink(link)
+	}, isRetryableError)
+	defer cancel()
+
+	result := <-ch
+	if result.Err != nil {
+		if isRetryableError(result.Err) {
+			acc.breaker.RecordFailure()
+		}
+		if errors.Is(result.Err, request.TrafficExceededError) {
+			acc.quota.RecordExceeded()
+		}
+	} else {
+		acc.breaker.RecordSuccess()
+	}
+	return result.Value, result.Err
+}
+
+// unrestrictLinks unrestricts multiple links concurrently, bounded by
+// ConcurrentRequests, routing each call through the transfer manager. If
+// ctx is cancelled partway through, in-flight calls are allowed to drain
+// but no new ones are started.
+func (s *Service) unrestrictLinks(ctx context.Context, acc *providerAccount, links []missingLink, dryRun bool) ([]*debrid.Download, []string, int) {
 	if dryRun {
 		s.logger.Info().Int("count", len(links)).Msg("[DRY-RUN] Would unrestrict links")
 		return nil, nil, 0
 	}
 
 	var mu sync.Mutex
-	var results []*realdebrid.Download
+	var results []*debrid.Download
 	var failed []string
 	completed := 0
 	queued := 0
-	var progress *console.ProgressBar
 
-	if logger.IsInfoEnabled() && logger.IsTTY() && !logger.IsDebugEnabled() {
-		progress = console.NewProgressBar("Unrestricting links", len(links))
-		progress.Update(0)
+	var mgr *console.ProgressManager
+	if logger.IsInfoEnabled() && !logger.IsDebugEnabled() {
+		mgr = console.NewProgressManager()
+		defer mgr.Stop()
 	}
 
-	pool := worker.NewPool(s.config.ConcurrentRequests)
+	startTime := time.Now()
+	sem := make(chan struct{}, s.config.ConcurrentRequests)
+	var wg sync.WaitGroup
 
 	for _, ml := range links {
+		if ctx.Err() != nil {
+			break
+		}
+
 		ml := ml // capture
-		pool.Submit(func() {
-			download, err := s.rd.UnrestrictLink(ml.link)
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var bar *console.Bar
+			if mgr != nil {
+				bar = mgr.AddBar(ml.torrent.Filename, 1)
+			}
+
+			download, err := s.unrestrictViaManager(ctx, acc, ml.link)
+
+			if bar != nil {
+				bar.Add(1)
+				bar.Done()
+			}
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -370,7 +698,7 @@ func (s *Service) unrestrictLinks(links []missingLink, dryRun bool) ([]*realdebr
 				// Check if it's a retryable error (503, 502, 504)
 				if isRetryableError(err) {
 					// Add to retry queue for next cycle
-					s.addToRetryQueue(ml.link, ml.torrent, err)
+					s.addToRetryQueue(ml.link, ml.torrent, acc.name, err)
 					queued++
 					s.logger.Debug().
 						Str("filename", ml.torrent.Filename).
@@ -385,9 +713,7 @@ func (s *Service) unrestrictLinks(links []missingLink, dryRun bool) ([]*realdebr
 				results = append(results, download)
 			}
 
-			if progress != nil {
-				progress.Update(completed)
-			} else if completed%100 == 0 || completed == len(links) {
+			if mgr == nil && (completed%100 == 0 || completed == len(links)) {
 				s.logger.Info().
 					Int("completed", completed).
 					Int("total", len(links)).
@@ -395,10 +721,23 @@ func (s *Service) unrestrictLinks(links []missingLink, dryRun bool) ([]*realdebr
 					Int("failed", len(failed)).
 					Msg("Unrestriction progress")
 			}
-		})
+
+			var etaSeconds float64
+			if elapsed := time.Since(startTime); completed > 0 {
+				etaSeconds = (elapsed.Seconds() / float64(completed)) * float64(len(links)-completed)
+			}
+			s.progress.Publish(progress.Event{
+				Phase:      "unrestrict",
+				Completed:  completed,
+				Total:      len(links),
+				Item:       ml.torrent.Filename,
+				ETASeconds: etaSeconds,
+				Timestamp:  time.Now(),
+			})
+		}()
 	}
 
-	pool.Wait()
+	wg.Wait()
 
 	// Save retry queue if any items were added
 	if !dryRun && s.retryQueue.Count() > 0 {
@@ -416,14 +755,11 @@ type candidateStats struct {
 	FilteredOther int
 }
 
-// buildCandidatesInto builds STRM candidates from torrents and downloads, reusing the provided slice.
-func (s *Service) buildCandidatesInto(torrents []*realdebrid.Torrent, downloadMap map[string]*realdebrid.Download, candidates []realdebrid.STRMCandidate, stats *candidateStats) []realdebrid.STRMCandidate {
+// buildCandidatesInto builds STRM candidates from one account's torrents
+// and downloads, stamping each with providerName, and appends them to the
+// provided slice.
+func (s *Service) buildCandidatesInto(providerName string, torrents []*debrid.Torrent, downloadMap map[string]*debrid.Download, candidates []realdebrid.STRMCandidate, stats *candidateStats) []realdebrid.STRMCandidate {
 	minSize := s.config.MinFileSizeBytes()
-	if stats != nil {
-		stats.Candidates = 0
-		stats.FilteredSmall = 0
-		stats.FilteredOther = 0
-	}
 
 	for _, torrent := range torrents {
 		for _, link := range torrent.Links {
@@ -467,6 +803,7 @@ func (s *Service) buildCandidatesInto(torrents []*realdebrid.Torrent, downloadMa
 				DownloadURL:   download.Download,
 				Link:          download.Link,
 				Filesize:      download.Filesize,
+				Provider:      providerName,
 			})
 		}
 	}
@@ -478,13 +815,13 @@ func (s *Service) buildCandidatesInto(torrents []*realdebrid.Torrent, downloadMa
 }
 
 // findTorrentsForLinks finds torrents that contain the given failed links
-func (s *Service) findTorrentsForLinks(torrents []*realdebrid.Torrent, failedLinks []string) []*realdebrid.Torrent {
+func (s *Service) findTorrentsForLinks(torrents []*debrid.Torrent, failedLinks []string) []*debrid.Torrent {
 	failedSet := make(map[string]bool)
 	for _, link := range failedLinks {
 		failedSet[link] = true
 	}
 
-	torrentSet := make(map[string]*realdebrid.Torrent)
+	torrentSet := make(map[string]*debrid.Torrent)
 	for _, torrent := range torrents {
 		for _, link := range torrent.Links {
 			if failedSet[link] {
@@ -494,7 +831,7 @@ func (s *Service) findTorrentsForLinks(torrents []*realdebrid.Torrent, failedLin
 		}
 	}
 
-	result := make([]*realdebrid.Torrent, 0, len(torrentSet))
+	result := make([]*debrid.Torrent, 0, len(torrentSet))
 	for _, t := range torrentSet {
 		result = append(result, t)
 	}
@@ -502,7 +839,7 @@ func (s *Service) findTorrentsForLinks(torrents []*realdebrid.Torrent, failedLin
 }
 
 // countTotalLinks counts total links across all torrents
-func countTotalLinks(torrents []*realdebrid.Torrent) int {
+func countTotalLinks(torrents []*debrid.Torrent) int {
 	count := 0
 	for _, t := range torrents {
 		count += len(t.Links)
@@ -521,19 +858,27 @@ type OrganizerResult struct {
 }
 
 // runOrganizer executes the Go organizer to organize files using ptt-go.
-func (s *Service) runOrganizer() OrganizerResult {
+func (s *Service) runOrganizer(ctx context.Context) OrganizerResult {
 	s.logger.Debug().Msg("Running library organizer...")
 
 	org := organizer.New(organizer.Config{
-		BaseDir:      s.config.Path,
-		OrganizedDir: s.config.OrganizedDir,
-		OutputDir:    s.config.OutputDir,
-		TrackingFile: s.config.TrackingFile,
-		CacheDir:     s.config.CacheDir,
-		Logger:       s.logger,
+		BaseDir:        s.config.Path,
+		OrganizedDir:   s.config.OrganizedDir,
+		OutputDir:      s.config.OutputDir,
+		TrackingFile:   s.config.TrackingFile,
+		CacheDir:       s.config.CacheDir,
+		Store:          s.store,
+		Logger:         s.logger,
+		CategoryLookup: s.qbittorrentCategory,
+		DiskGuard:      s.diskGuard,
+		HashAlgorithm:  s.config.OrganizerHashAlgorithm,
+		PlacementMode:  organizer.PlacementMode(s.config.OrganizerPlacementMode),
 	})
 
-	result := org.Run()
+	result, err := org.RunContext(ctx, nil)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Organizer sweep stopped early")
+	}
 
 	s.logger.Debug().
 		Int("processed", result.Processed).
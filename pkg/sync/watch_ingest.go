@@ -0,0 +1,139 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/robofuse/robofuse/internal/debounce"
+	"github.com/robofuse/robofuse/pkg/torrentfile"
+)
+
+// watch_ingest.go lets Watch react to .magnet/.torrent files dropped into
+// config.WatchDir: each is submitted to Real-Debrid and triggers an
+// immediate cycle, instead of waiting for the next WatchModeInterval tick.
+
+// ingestDebounce is how long a dropped file must sit quiet before it's
+// read, mirroring the delay downloaders use to wait out an atomic
+// rename/move rather than reading a half-written file.
+const ingestDebounce = 3 * time.Second
+
+// startIngestWatcher starts a fsnotify watch on s.config.WatchDir, calling
+// trigger for each magnet/torrent file successfully submitted to
+// Real-Debrid. It returns a nil watcher (and no error) when WatchDir is
+// unset, so callers can always defer-close the result.
+func (s *Service) startIngestWatcher(trigger func()) (*fsnotify.Watcher, error) {
+	if s.config.WatchDir == "" {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(s.config.WatchDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating watch dir: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("starting watch-dir watcher: %w", err)
+	}
+
+	if err := watcher.Add(s.config.WatchDir); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("watching %s: %w", s.config.WatchDir, err)
+	}
+
+	deb := debounce.New(ingestDebounce, func(path string) {
+		if s.ingestWatchFile(path) {
+			trigger()
+		}
+	})
+
+	go func() {
+		defer deb.Stop()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) && !event.Has(fsnotify.Chmod) {
+					continue
+				}
+				if !isIngestibleFile(event.Name) {
+					continue
+				}
+				deb.Trigger(event.Name)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				s.logger.Warn().Err(err).Msg("Watch-dir watcher error")
+			}
+		}
+	}()
+
+	s.logger.Info().Str("dir", s.config.WatchDir).Msg("Watching directory for dropped magnet/torrent files")
+	return watcher, nil
+}
+
+// isIngestibleFile reports whether path's extension is one watchIngester
+// acts on.
+func isIngestibleFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".magnet", ".torrent":
+		return true
+	default:
+		return false
+	}
+}
+
+// ingestWatchFile submits a single dropped file to Real-Debrid and removes
+// it on success, reporting whether a sync should be triggered.
+func (s *Service) ingestWatchFile(path string) bool {
+	if _, err := os.Stat(path); err != nil {
+		// Removed or renamed away before the debounce fired.
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("path", path).Msg("Failed to read dropped file")
+		return false
+	}
+
+	var torrentID string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".magnet":
+		hash, _, name, err := torrentfile.ParseMagnetOrTorrent(string(data))
+		if err != nil {
+			s.logger.Warn().Err(err).Str("path", path).Msg("Failed to parse dropped magnet")
+			return false
+		}
+		torrentID, err = s.rd.AddMagnet(hash)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("name", name).Msg("Failed to add dropped magnet")
+			return false
+		}
+
+	case ".torrent":
+		torrentID, err = s.rd.AddTorrentFile(data)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("path", path).Msg("Failed to add dropped torrent")
+			return false
+		}
+
+	default:
+		return false
+	}
+
+	s.logger.Info().Str("id", torrentID).Str("path", path).Msg("Added dropped file to Real-Debrid")
+
+	if err := os.Remove(path); err != nil {
+		s.logger.Warn().Err(err).Str("path", path).Msg("Failed to remove ingested file")
+	}
+
+	return true
+}
@@ -0,0 +1,117 @@
+package torrentfile
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// bdecoder is a minimal bencode decoder - just enough to walk a .torrent
+// file's dictionary structure and recover the raw bytes of each top-level
+// value, since the infohash must be computed over "info" exactly as it
+// appeared on the wire rather than a re-encoded copy.
+type bdecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *bdecoder) decode() (any, error) {
+	if d.pos >= len(d.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		return d.decodeInt()
+	case c == 'l':
+		return d.decodeList()
+	case c == 'd':
+		v, _, err := d.decodeDict()
+		return v, err
+	case c >= '0' && c <= '9':
+		return d.decodeBytes()
+	default:
+		return nil, fmt.Errorf("bencode: unexpected byte %q at offset %d", c, d.pos)
+	}
+}
+
+func (d *bdecoder) decodeInt() (int64, error) {
+	d.pos++ // skip 'i'
+	end := d.indexByte('e')
+	if end < 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	n, err := strconv.ParseInt(string(d.data[d.pos:end]), 10, 64)
+	d.pos = end + 1
+	return n, err
+}
+
+func (d *bdecoder) decodeBytes() ([]byte, error) {
+	colon := d.indexByte(':')
+	if colon < 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	n, err := strconv.Atoi(string(d.data[d.pos:colon]))
+	if err != nil {
+		return nil, fmt.Errorf("bencode: bad string length: %w", err)
+	}
+	start := colon + 1
+	if n < 0 || start+n > len(d.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	d.pos = start + n
+	return d.data[start : start+n], nil
+}
+
+func (d *bdecoder) decodeList() ([]any, error) {
+	d.pos++ // skip 'l'
+	var list []any
+	for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	if d.pos >= len(d.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	d.pos++ // skip 'e'
+	return list, nil
+}
+
+// decodeDict decodes a dictionary, returning both the decoded values and
+// the raw bencoded bytes behind each key - the latter is what lets
+// parseTorrentFile hash "info" exactly as it appeared on the wire.
+func (d *bdecoder) decodeDict() (map[string]any, map[string][]byte, error) {
+	d.pos++ // skip 'd'
+	values := make(map[string]any)
+	raw := make(map[string][]byte)
+
+	for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+		key, err := d.decodeBytes()
+		if err != nil {
+			return nil, nil, err
+		}
+		valueStart := d.pos
+		value, err := d.decode()
+		if err != nil {
+			return nil, nil, err
+		}
+		values[string(key)] = value
+		raw[string(key)] = d.data[valueStart:d.pos]
+	}
+	if d.pos >= len(d.data) {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	d.pos++ // skip 'e'
+	return values, raw, nil
+}
+
+func (d *bdecoder) indexByte(b byte) int {
+	for i := d.pos; i < len(d.data); i++ {
+		if d.data[i] == b {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,61 @@
+package torrentfile
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// encode.go is bdecoder's write-side counterpart, used by writers (e.g.
+// pkg/export/qbittorrent) that need to emit bencode rather than just parse
+// it. It only needs to round-trip the handful of Go types bencode itself
+// can represent.
+
+// Encode bencodes v, which must be built from string, []byte, int, int64,
+// []any, and map[string]any (dict keys are sorted, as bencode requires).
+func Encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeValue(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, v any) error {
+	switch val := v.(type) {
+	case string:
+		fmt.Fprintf(buf, "%d:%s", len(val), val)
+	case []byte:
+		fmt.Fprintf(buf, "%d:", len(val))
+		buf.Write(val)
+	case int:
+		fmt.Fprintf(buf, "i%de", val)
+	case int64:
+		fmt.Fprintf(buf, "i%de", val)
+	case []any:
+		buf.WriteByte('l')
+		for _, item := range val {
+			if err := encodeValue(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('e')
+	case map[string]any:
+		buf.WriteByte('d')
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(buf, "%d:%s", len(k), k)
+			if err := encodeValue(buf, val[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('e')
+	default:
+		return fmt.Errorf("bencode: unsupported type %T", v)
+	}
+	return nil
+}
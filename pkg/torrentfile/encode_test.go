@@ -0,0 +1,38 @@
+package torrentfile
+
+import "testing"
+
+func TestEncode_RoundTripsThroughDecoder(t *testing.T) {
+	data, err := Encode(map[string]any{
+		"name":  "movie.mkv",
+		"files": []any{"a", "b"},
+		"size":  int64(1024),
+	})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	d := &bdecoder{data: data}
+	values, _, err := d.decodeDict()
+	if err != nil {
+		t.Fatalf("decoding encoded output: %v", err)
+	}
+
+	if name, ok := values["name"].([]byte); !ok || string(name) != "movie.mkv" {
+		t.Errorf("name = %v", values["name"])
+	}
+	if size, ok := values["size"].(int64); !ok || size != 1024 {
+		t.Errorf("size = %v", values["size"])
+	}
+}
+
+func TestEncode_SortsDictKeys(t *testing.T) {
+	data, err := Encode(map[string]any{"z": 1, "a": 2})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	want := "d1:ai2e1:zi1ee"
+	if string(data) != want {
+		t.Errorf("Encode = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,103 @@
+// Package torrentfile parses magnet URIs and bencoded .torrent files down
+// to the SHA-1 infohash robofuse keys everything on, without pulling in a
+// full BitTorrent client library.
+package torrentfile
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ParseMagnetOrTorrent decodes input as either a magnet URI or the path to
+// a bencoded .torrent file, returning its infohash, announce trackers, and
+// display name.
+func ParseMagnetOrTorrent(input string) (infoHash string, trackers []string, name string, err error) {
+	trimmed := strings.TrimSpace(input)
+	if strings.HasPrefix(trimmed, "magnet:") {
+		return parseMagnet(trimmed)
+	}
+
+	data, err := os.ReadFile(trimmed)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("reading torrent file: %w", err)
+	}
+	return parseTorrentFile(data)
+}
+
+// ParseTorrentBytes is ParseMagnetOrTorrent's .torrent-file path for
+// callers that already have the raw bencoded bytes in memory (e.g. an
+// uploaded file) instead of a path on disk.
+func ParseTorrentBytes(data []byte) (infoHash string, trackers []string, name string, err error) {
+	return parseTorrentFile(data)
+}
+
+// parseMagnet extracts the btih infohash, trackers ("tr" params), and
+// display name ("dn") from a magnet URI.
+func parseMagnet(magnet string) (string, []string, string, error) {
+	u, err := url.Parse(magnet)
+	if err != nil || u.Scheme != "magnet" {
+		return "", nil, "", fmt.Errorf("torrentfile: not a magnet URI")
+	}
+
+	q := u.Query()
+	var hash string
+	for _, xt := range q["xt"] {
+		if h, ok := strings.CutPrefix(xt, "urn:btih:"); ok {
+			hash = strings.ToLower(h)
+			break
+		}
+	}
+	if hash == "" {
+		return "", nil, "", fmt.Errorf("torrentfile: magnet has no btih infohash")
+	}
+
+	return hash, q["tr"], q.Get("dn"), nil
+}
+
+// parseTorrentFile decodes a bencoded .torrent file and hashes its "info"
+// dictionary's raw bytes to recover the infohash the same way a BitTorrent
+// client would.
+func parseTorrentFile(data []byte) (string, []string, string, error) {
+	d := &bdecoder{data: data}
+	top, rawByKey, err := d.decodeDict()
+	if err != nil {
+		return "", nil, "", fmt.Errorf("torrentfile: %w", err)
+	}
+
+	infoRaw, ok := rawByKey["info"]
+	if !ok {
+		return "", nil, "", fmt.Errorf("torrentfile: missing info dictionary")
+	}
+	sum := sha1.Sum(infoRaw)
+
+	var trackers []string
+	if announce, ok := top["announce"].([]byte); ok {
+		trackers = append(trackers, string(announce))
+	}
+	if list, ok := top["announce-list"].([]any); ok {
+		for _, tier := range list {
+			tierList, ok := tier.([]any)
+			if !ok {
+				continue
+			}
+			for _, t := range tierList {
+				if b, ok := t.([]byte); ok {
+					trackers = append(trackers, string(b))
+				}
+			}
+		}
+	}
+
+	name := ""
+	if info, ok := top["info"].(map[string]any); ok {
+		if n, ok := info["name"].([]byte); ok {
+			name = string(n)
+		}
+	}
+
+	return hex.EncodeToString(sum[:]), trackers, name, nil
+}
@@ -0,0 +1,62 @@
+package torrentfile
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// torrentfile_test.go guards the infohash extraction both magnet URIs and
+// bencoded .torrent files are expected to agree on.
+
+func TestParseMagnetOrTorrent_Magnet(t *testing.T) {
+	magnet := "magnet:?xt=urn:btih:ABCDEF0123456789ABCDEF0123456789ABCDEF01&dn=Some+Movie&tr=udp%3A%2F%2Ftracker.example.com%3A80"
+
+	hash, trackers, name, err := ParseMagnetOrTorrent(magnet)
+	if err != nil {
+		t.Fatalf("ParseMagnetOrTorrent: %v", err)
+	}
+	if hash != "abcdef0123456789abcdef0123456789abcdef01" {
+		t.Errorf("hash = %q", hash)
+	}
+	if name != "Some Movie" {
+		t.Errorf("name = %q", name)
+	}
+	if len(trackers) != 1 || trackers[0] != "udp://tracker.example.com:80" {
+		t.Errorf("trackers = %v", trackers)
+	}
+}
+
+func TestParseMagnetOrTorrent_TorrentFile(t *testing.T) {
+	info := "d6:lengthi1024e4:name9:movie.mkve"
+	torrent := "d8:announce24:udp://tracker.example:804:info" + info + "e"
+
+	path := filepath.Join(t.TempDir(), "test.torrent")
+	if err := os.WriteFile(path, []byte(torrent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, trackers, name, err := ParseMagnetOrTorrent(path)
+	if err != nil {
+		t.Fatalf("ParseMagnetOrTorrent: %v", err)
+	}
+
+	want := sha1.Sum([]byte(info))
+	if hash != hex.EncodeToString(want[:]) {
+		t.Errorf("hash = %q, want %x", hash, want)
+	}
+	if name != "movie.mkv" {
+		t.Errorf("name = %q", name)
+	}
+	if len(trackers) != 1 || trackers[0] != "udp://tracker.example:80" {
+		t.Errorf("trackers = %v", trackers)
+	}
+}
+
+func TestParseMagnetOrTorrent_RejectsGarbage(t *testing.T) {
+	if _, _, _, err := ParseMagnetOrTorrent("not a magnet or a real path"); err == nil {
+		t.Fatal("expected error for unreadable input")
+	}
+}
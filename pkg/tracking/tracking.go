@@ -2,11 +2,10 @@ package tracking
 
 import (
 	"encoding/json"
-	"os"
-	"sync"
 	"time"
 
 	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/internal/store"
 	"github.com/rs/zerolog"
 )
 
@@ -18,134 +17,147 @@ type FileTracking struct {
 	CreatedAt    time.Time `json:"created_at"`
 	LastChecked  time.Time `json:"last_checked"`
 	TorrentID    string    `json:"torrent_id"`
+	// Backend identifies which strm.Writer produced this file (e.g.
+	// "local", "webdav"), so a run against a different backend doesn't
+	// mistake another backend's files for orphans.
+	Backend string `json:"backend"`
+	// Provider identifies which debrid account produced this file (e.g.
+	// "real-debrid", "alldebrid"), so the organizer can tell an account
+	// swap apart from a same-provider refresh.
+	Provider string `json:"provider,omitempty"`
 }
 
-// Service manages file tracking persistence
+// Service manages file tracking persistence, backed by the shared bbolt
+// store's "tracking" bucket instead of a JSON file rewritten wholesale on
+// every change.
 type Service struct {
-	trackingFile string
-	data         map[string]*FileTracking
-	mu           sync.RWMutex
-	logger       zerolog.Logger
+	store  *store.DB
+	logger zerolog.Logger
 }
 
-// New creates a new tracking service
-func New(trackingFile string) *Service {
+// New creates a tracking Service backed by db. legacyJSONPath is the old
+// file_tracking.json path; if the tracking bucket is still empty, its
+// contents are imported one time and the file is moved to *.bak.
+func New(db *store.DB, legacyJSONPath string) *Service {
 	s := &Service{
-		trackingFile: trackingFile,
-		data:         make(map[string]*FileTracking),
-		logger:       logger.New("tracking"),
+		store:  db,
+		logger: logger.New("tracking"),
 	}
 
-	// Load existing data
-	if err := s.Load(); err != nil {
-		s.logger.Debug().Err(err).Msg("No existing tracking file, starting fresh")
+	n, err := store.MigrateJSONFile(db, legacyJSONPath, store.TrackingBucket)
+	if err != nil {
+		s.logger.Warn().Err(err).Msg("Failed to migrate legacy file_tracking.json")
+	} else if n > 0 {
+		s.logger.Info().Int("count", n).Msg("Migrated legacy file_tracking.json into store")
 	}
 
 	return s
 }
 
-// Track records or updates tracking data for a file
-func (s *Service) Track(relativePath, downloadURL, link, torrentID string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
+// Track records or updates tracking data for a file, recording which
+// backend and debrid provider produced it.
+func (s *Service) Track(relativePath, downloadURL, link, torrentID, backend, provider string) {
 	now := time.Now()
 
-	if existing, exists := s.data[relativePath]; exists {
-		// Update existing entry
-		existing.DownloadURL = downloadURL
-		existing.Link = link
-		existing.LastChecked = now
+	entry := FileTracking{
+		RelativePath: relativePath,
+		DownloadURL:  downloadURL,
+		Link:         link,
+		CreatedAt:    now,
+		LastChecked:  now,
+		TorrentID:    torrentID,
+		Backend:      backend,
+		Provider:     provider,
+	}
+
+	var existing FileTracking
+	if found, err := s.store.Get(store.TrackingBucket, relativePath, &existing); err == nil && found {
+		entry.CreatedAt = existing.CreatedAt
 		s.logger.Debug().Str("path", relativePath).Msg("Updated tracking")
 	} else {
-		// Create new entry
-		s.data[relativePath] = &FileTracking{
-			RelativePath: relativePath,
-			DownloadURL:  downloadURL,
-			Link:         link,
-			CreatedAt:    now,
-			LastChecked:  now,
-			TorrentID:    torrentID,
-		}
 		s.logger.Debug().Str("path", relativePath).Msg("Started tracking")
 	}
+
+	if err := s.store.Put(store.TrackingBucket, relativePath, &entry); err != nil {
+		s.logger.Error().Err(err).Str("path", relativePath).Msg("Failed to persist tracking")
+	}
 }
 
 // GetExpired returns tracking data for files older than the specified duration
 func (s *Service) GetExpired(olderThan time.Duration) []*FileTracking {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	threshold := time.Now().Add(-olderThan)
 	var expired []*FileTracking
 
-	for _, tracking := range s.data {
-		if tracking.CreatedAt.Before(threshold) {
-			expired = append(expired, tracking)
+	s.store.ForEach(store.TrackingBucket, func(key string, raw []byte) error {
+		var ft FileTracking
+		if err := json.Unmarshal(raw, &ft); err != nil {
+			return nil
 		}
-	}
+		if ft.CreatedAt.Before(threshold) {
+			expired = append(expired, &ft)
+		}
+		return nil
+	})
 
 	return expired
 }
 
 // Get retrieves tracking data for a specific path
 func (s *Service) Get(relativePath string) (*FileTracking, bool) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	tracking, exists := s.data[relativePath]
-	return tracking, exists
-}
-
-// Remove deletes tracking data for a file
-func (s *Service) Remove(relativePath string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	delete(s.data, relativePath)
-	s.logger.Debug().Str("path", relativePath).Msg("Removed tracking")
+	var ft FileTracking
+	found, err := s.store.Get(store.TrackingBucket, relativePath, &ft)
+	if err != nil || !found {
+		return nil, false
+	}
+	return &ft, true
 }
 
-// Save persists tracking data to disk
-func (s *Service) Save() error {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// ForBackend returns the download URL of every tracked file produced by the
+// given backend, keyed by relative path. Used to reconstruct "existing"
+// output without listing a remote backend that may not support it cheaply.
+func (s *Service) ForBackend(backend string) map[string]string {
+	out := make(map[string]string)
 
-	data, err := json.MarshalIndent(s.data, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	if err := os.WriteFile(s.trackingFile, data, 0644); err != nil {
-		return err
-	}
+	s.store.ForEach(store.TrackingBucket, func(key string, raw []byte) error {
+		var ft FileTracking
+		if err := json.Unmarshal(raw, &ft); err != nil {
+			return nil
+		}
+		if ft.Backend == backend {
+			out[key] = ft.DownloadURL
+		}
+		return nil
+	})
 
-	s.logger.Debug().Int("count", len(s.data)).Msg("Saved tracking data")
-	return nil
+	return out
 }
 
-// Load reads tracking data from disk
-func (s *Service) Load() error {
-	data, err := os.ReadFile(s.trackingFile)
-	if err != nil {
-		return err
-	}
-
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if err := json.Unmarshal(data, &s.data); err != nil {
-		return err
+// Remove deletes tracking data for a file
+func (s *Service) Remove(relativePath string) {
+	if err := s.store.Delete(store.TrackingBucket, relativePath); err != nil {
+		s.logger.Error().Err(err).Str("path", relativePath).Msg("Failed to remove tracking")
+		return
 	}
-
-	s.logger.Info().Int("count", len(s.data)).Msg("Loaded tracking data")
-	return nil
+	s.logger.Debug().Str("path", relativePath).Msg("Removed tracking")
 }
 
 // Count returns the number of tracked files
 func (s *Service) Count() int {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	return s.store.Count(store.TrackingBucket)
+}
+
+// All returns a snapshot copy of every tracked file, keyed by relative path.
+func (s *Service) All() map[string]*FileTracking {
+	out := make(map[string]*FileTracking)
+
+	s.store.ForEach(store.TrackingBucket, func(key string, raw []byte) error {
+		var ft FileTracking
+		if err := json.Unmarshal(raw, &ft); err != nil {
+			return nil
+		}
+		out[key] = &ft
+		return nil
+	})
 
-	return len(s.data)
+	return out
 }
@@ -1,10 +1,12 @@
 package tracking
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/internal/store"
 )
 
 // tracking_test.go guards expiry behavior across CreatedAt/LastChecked values.
@@ -13,24 +15,29 @@ func TestGetExpired_UsesLastCheckedFallbackCreatedAt(t *testing.T) {
 	now := time.Now()
 	olderThan := 6 * 24 * time.Hour
 
+	db, err := store.Open(filepath.Join(t.TempDir(), "robofuse.db"))
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	defer db.Close()
+
 	svc := &Service{
-		trackingFile: "",
-		data:         make(map[string]*FileTracking),
-		logger:       logger.New("test"),
+		store:  db,
+		logger: logger.New("test"),
 	}
 
 	// Old created, but recently checked: should NOT be expired.
-	svc.data["recent-check"] = &FileTracking{
+	db.Put(store.TrackingBucket, "recent-check", &FileTracking{
 		RelativePath: "recent-check",
 		CreatedAt:    now.Add(-10 * 24 * time.Hour),
 		LastChecked:  now.Add(-1 * time.Hour),
-	}
+	})
 
 	// Old created and never checked: should be expired.
-	svc.data["never-checked"] = &FileTracking{
+	db.Put(store.TrackingBucket, "never-checked", &FileTracking{
 		RelativePath: "never-checked",
 		CreatedAt:    now.Add(-10 * 24 * time.Hour),
-	}
+	})
 
 	expired := svc.GetExpired(olderThan)
 
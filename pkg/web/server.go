@@ -0,0 +1,222 @@
+// Package web serves a small read-mostly dashboard over a running sync
+// Service: the last RunResult, live cycle progress, the retry queue, and a
+// browsable view of the tracked STRM library, with buttons to trigger a
+// run or force-refresh a single file. It has no external dependencies at
+// runtime - assets are embedded via embed.FS.
+package web
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sort"
+
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/robofuse/robofuse/pkg/retry"
+	"github.com/robofuse/robofuse/pkg/sync"
+	"github.com/rs/zerolog"
+)
+
+//go:embed assets
+var assetsFS embed.FS
+
+// Server serves the dashboard over HTTP.
+type Server struct {
+	addr       string
+	adminToken string
+	service    *sync.Service
+	logger     zerolog.Logger
+}
+
+// NewServer creates a Server that will listen on addr once started.
+// adminToken gates every /api/ route, including the read-only ones, since
+// the dashboard exposes the full tracked library; an empty token disables
+// that gate, matching metrics.NewServer's /debug/queue convention.
+func NewServer(addr, adminToken string, service *sync.Service) *Server {
+	return &Server{
+		addr:       addr,
+		adminToken: adminToken,
+		service:    service,
+		logger:     logger.New("web"),
+	}
+}
+
+// ListenAndServe serves until ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	static, err := fs.Sub(assetsFS, "assets")
+	if err != nil {
+		return fmt.Errorf("loading embedded assets: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.HandleFunc("/api/status", s.requireAdminToken(s.handleStatus))
+	mux.HandleFunc("/api/library", s.requireAdminToken(s.handleLibrary))
+	mux.HandleFunc("/api/retry", s.requireAdminToken(s.handleRetry))
+	mux.HandleFunc("/api/progress", s.requireAdminToken(s.handleProgress))
+	mux.HandleFunc("/api/trigger", s.requireAdminToken(s.handleTrigger))
+	mux.HandleFunc("/api/refresh", s.requireAdminToken(s.handleRefresh))
+
+	srv := &http.Server{Addr: s.addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	s.logger.Info().Str("addr", s.addr).Msg("Web dashboard listening")
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// requireAdminToken gates next behind the same admin_token header check as
+// metrics.Server's /debug/queue. An empty adminToken disables the dashboard's
+// API entirely rather than leaving it open, since unlike /metrics this
+// surface can trigger syncs and dumps the whole tracked library.
+func (s *Server) requireAdminToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.adminToken == "" || r.Header.Get("admin_token") != s.adminToken {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.service.Status())
+}
+
+// libraryEntry is one tracked STRM file, grouped by torrent for display.
+type libraryEntry struct {
+	RelativePath string `json:"relative_path"`
+	Provider     string `json:"provider"`
+	LastChecked  string `json:"last_checked"`
+}
+
+func (s *Server) handleLibrary(w http.ResponseWriter, r *http.Request) {
+	snapshot := s.service.TrackingSnapshot()
+
+	grouped := make(map[string][]libraryEntry)
+	for _, t := range snapshot {
+		grouped[t.TorrentID] = append(grouped[t.TorrentID], libraryEntry{
+			RelativePath: t.RelativePath,
+			Provider:     t.Provider,
+			LastChecked:  t.LastChecked.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, entries := range grouped {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].RelativePath < entries[j].RelativePath })
+	}
+
+	writeJSON(w, grouped)
+}
+
+func (s *Server) handleRetry(w http.ResponseWriter, r *http.Request) {
+	items := s.service.RetryQueueItems()
+	if items == nil {
+		items = []*retry.RetryItem{}
+	}
+	writeJSON(w, items)
+}
+
+// handleProgress streams the current cycle's progress.Event values as
+// server-sent events until the client disconnects.
+func (s *Server) handleProgress(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch, cancel := s.service.Progress().Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// handleTrigger kicks off a sync cycle in the background and returns
+// immediately, mirroring rpc.Server's TriggerSync.
+func (s *Server) handleTrigger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var params struct {
+		DryRun bool `json:"dry_run"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			http.Error(w, "decoding request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	go func() {
+		if _, err := s.service.TriggerSync(context.Background(), params.DryRun); err != nil {
+			s.logger.Warn().Err(err).Msg("Dashboard-triggered sync failed")
+		}
+	}()
+
+	writeJSON(w, map[string]string{"status": "triggered"})
+}
+
+// handleRefresh force-refreshes a single tracked STRM file's download link.
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var params struct {
+		Path string `json:"path"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "decoding request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if params.Path == "" {
+		http.Error(w, "path is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.service.RefreshFile(r.Context(), params.Path); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]string{"status": "refreshed"})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,197 @@
+// Package xfer provides a deduplicating transfer manager: concurrent callers
+// asking for the same keyed piece of work (e.g. unrestricting the same
+// Real-Debrid link) share a single in-flight call instead of racing the
+// upstream API.
+package xfer
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/robofuse/robofuse/internal/logger"
+	"github.com/rs/zerolog"
+)
+
+// manager.go implements per-key in-flight deduplication with refcounted
+// cancellation and exponential backoff with jitter on retryable failures.
+
+// Result carries the outcome of a transfer.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// BackoffConfig controls retry pacing for retryable failures.
+type BackoffConfig struct {
+	Base        time.Duration // delay before the first retry
+	Max         time.Duration // cap on the delay
+	Jitter      float64       // +/- fraction of the delay to randomize, e.g. 0.25
+	MaxAttempts int           // give up after this many attempts; 0 means unlimited
+}
+
+// Manager deduplicates concurrent transfers keyed by a caller-chosen string
+// (typically a Real-Debrid link), retrying retryable failures with backoff.
+type Manager[T any] struct {
+	backoff BackoffConfig
+	logger  zerolog.Logger
+
+	mu       sync.Mutex
+	inFlight map[string]*call[T]
+}
+
+// call tracks a single in-flight (or just-finished) transfer and its watchers.
+type call[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	refs     int
+	watchers []chan Result[T]
+	done     bool
+	result   Result[T]
+}
+
+// NewManager creates a Manager with the given backoff policy.
+func NewManager[T any](backoff BackoffConfig) *Manager[T] {
+	if backoff.Base <= 0 {
+		backoff.Base = time.Second
+	}
+	if backoff.Max <= 0 {
+		backoff.Max = 30 * time.Second
+	}
+	return &Manager[T]{
+		backoff:  backoff,
+		logger:   logger.New("xfer"),
+		inFlight: make(map[string]*call[T]),
+	}
+}
+
+// Watch starts (or joins) the transfer identified by key. fn performs one
+// attempt; isRetryable decides whether a failed attempt should be retried
+// with backoff. Every caller gets its own result channel (closed after the
+// single value is delivered) and a cancel func. The underlying work is only
+// actually cancelled once every watcher of that key has called cancel.
+func (m *Manager[T]) Watch(ctx context.Context, key string, fn func(context.Context) (T, error), isRetryable func(error) bool) (<-chan Result[T], context.CancelFunc) {
+	ch := make(chan Result[T], 1)
+
+	m.mu.Lock()
+	c, exists := m.inFlight[key]
+	if !exists {
+		callCtx, cancel := context.WithCancel(context.Background())
+		c = &call[T]{ctx: callCtx, cancel: cancel}
+		m.inFlight[key] = c
+		m.mu.Unlock()
+
+		c.mu.Lock()
+		c.refs = 1
+		c.watchers = append(c.watchers, ch)
+		c.mu.Unlock()
+
+		go m.run(key, c, fn, isRetryable)
+	} else {
+		m.mu.Unlock()
+
+		c.mu.Lock()
+		if c.done {
+			result := c.result
+			c.mu.Unlock()
+			ch <- result
+			close(ch)
+			return ch, func() {}
+		}
+		c.refs++
+		c.watchers = append(c.watchers, ch)
+		c.mu.Unlock()
+
+		m.logger.Debug().Str("key", key).Msg("Joined in-flight transfer")
+	}
+
+	var once sync.Once
+	cancelFn := func() {
+		once.Do(func() {
+			c.mu.Lock()
+			c.refs--
+			remaining := c.refs
+			c.mu.Unlock()
+			if remaining <= 0 {
+				c.cancel()
+			}
+		})
+	}
+
+	return ch, cancelFn
+}
+
+// run executes fn with backoff-on-retryable-failure until it succeeds, fails
+// permanently, or the call's context is cancelled, then fans the result out
+// to every watcher.
+func (m *Manager[T]) run(key string, c *call[T], fn func(context.Context) (T, error), isRetryable func(error) bool) {
+	result := m.runWithBackoff(c.ctx, fn, isRetryable)
+
+	m.mu.Lock()
+	delete(m.inFlight, key)
+	m.mu.Unlock()
+
+	c.mu.Lock()
+	c.done = true
+	c.result = result
+	watchers := c.watchers
+	c.mu.Unlock()
+
+	for _, w := range watchers {
+		w <- result
+		close(w)
+	}
+}
+
+func (m *Manager[T]) runWithBackoff(ctx context.Context, fn func(context.Context) (T, error), isRetryable func(error) bool) Result[T] {
+	delay := m.backoff.Base
+	attempt := 0
+
+	for {
+		attempt++
+		value, err := fn(ctx)
+		if err == nil {
+			return Result[T]{Value: value}
+		}
+		if ctx.Err() != nil {
+			return Result[T]{Err: ctx.Err()}
+		}
+		if isRetryable == nil || !isRetryable(err) {
+			return Result[T]{Err: err}
+		}
+		if m.backoff.MaxAttempts > 0 && attempt >= m.backoff.MaxAttempts {
+			return Result[T]{Err: err}
+		}
+
+		select {
+		case <-ctx.Done():
+			return Result[T]{Err: ctx.Err()}
+		case <-time.After(withJitter(delay, m.backoff.Jitter)):
+		}
+
+		delay *= 2
+		if delay > m.backoff.Max {
+			delay = m.backoff.Max
+		}
+	}
+}
+
+// withJitter randomizes d by +/- fraction.
+func withJitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return d
+	}
+	spread := float64(d) * fraction
+	offset := (rand.Float64()*2 - 1) * spread
+	return time.Duration(float64(d) + offset)
+}
+
+// InFlightCount reports how many distinct keys currently have a transfer running.
+func (m *Manager[T]) InFlightCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.inFlight)
+}